@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+)
+
+// Response is the response of the admin API.
+type Response struct {
+	Status string          `json:"status"`
+	Result json.RawMessage `json:"result"`
+}
+
+// api is a client of the admin API of a game server.
+type api struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newAPI(url, token string) *api {
+	return &api{
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// do sends a request to the admin API, and returns the result of a
+// successful request.
+func (a *api) do(method, path string, body interface{}) (json.RawMessage, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal request: %s", err.Error())
+		}
+		reader = strings.NewReader(string(b))
+	}
+
+	req, err := http.NewRequest(method, a.url+manager.AdminAPIPath+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build request: %s", err.Error())
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reach admin API: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	var response Response
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode response (%s): %s", resp.Status, err.Error())
+	}
+
+	if response.Status != manager.OK {
+		var reason string
+		_ = json.Unmarshal(response.Result, &reason)
+		return nil, fmt.Errorf("%s: %s", resp.Status, reason)
+	}
+
+	return response.Result, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+)
+
+const usage = `usage: admin [flags] <command>
+
+commands:
+  games ls                       list the live games
+  games show <id>                show a game as seen by each of its players
+  games abort <id>               abort a game at once
+  players ls                     list the registered players
+  players kick <game> <player>   kick a player from a game
+  players ban <id>               unregister and ban a player
+  announce "<message>"           broadcast an announcement to all clients
+
+flags:
+`
+
+func main() {
+	url := flag.String("url", "http://localhost:8000", "URL of the game server")
+	token := flag.String("token", os.Getenv("ADMIN_TOKEN"), "admin token of the game server (defaults to $ADMIN_TOKEN)")
+	jsonOutput := flag.Bool("json", false, "print the results in JSON rather than tables")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	err := run(newAPI(*url, *token), flag.Args(), *jsonOutput)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+// run executes a command against the admin API.
+func run(a *api, args []string, jsonOutput bool) error {
+	if len(args) < 2 {
+		flag.Usage()
+		return fmt.Errorf("expected a command")
+	}
+
+	command := args[0] + " " + args[1]
+	switch {
+	case command == "games ls" && len(args) == 2:
+		result, err := a.do(http.MethodGet, "games", nil)
+		if err != nil || jsonOutput {
+			return printJSON(result, err)
+		}
+		return printGames(result)
+	case command == "games show" && len(args) == 3:
+		return printJSON(a.do(http.MethodGet, "games/"+args[2], nil))
+	case command == "games abort" && len(args) == 3:
+		_, err := a.do(http.MethodPost, "games/"+args[2]+"/abort", nil)
+		return done(err, "game %s aborted", args[2])
+	case command == "players ls" && len(args) == 2:
+		result, err := a.do(http.MethodGet, "players", nil)
+		if err != nil || jsonOutput {
+			return printJSON(result, err)
+		}
+		return printPlayers(result)
+	case command == "players kick" && len(args) == 4:
+		_, err := a.do(http.MethodPost, "games/"+args[2]+"/kick", manager.KickAdminData{IDPlayer: args[3]})
+		return done(err, "player %s kicked from game %s", args[3], args[2])
+	case command == "players ban" && len(args) == 3:
+		_, err := a.do(http.MethodPost, "players/"+args[2]+"/ban", nil)
+		return done(err, "player %s banned", args[2])
+	case len(args) == 2 && args[0] == "announce":
+		_, err := a.do(http.MethodPost, "announcements", manager.AnnouncementData{Message: args[1]})
+		return done(err, "announcement sent")
+	default:
+		flag.Usage()
+		return fmt.Errorf("unsupported command %q", strings.Join(args, " "))
+	}
+}
+
+// done prints the outcome of a command with no result.
+func done(err error, format string, a ...interface{}) error {
+	if err != nil {
+		return err
+	}
+	fmt.Printf(format+"\n", a...)
+	return nil
+}
+
+// printJSON prints the indented result of a request.
+func printJSON(result json.RawMessage, err error) error {
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	err = json.Indent(&b, result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to indent result: %s", err.Error())
+	}
+	fmt.Println(b.String())
+	return nil
+}
+
+// printGames prints the games in a table.
+func printGames(result json.RawMessage) error {
+	var games []struct {
+		Game struct {
+			ID         string `json:"id"`
+			Name       string `json:"Name"`
+			Type       string `json:"type"`
+			MaxPlayers int    `json:"maxPlayers"`
+			Host       string `json:"host"`
+			Private    bool   `json:"private"`
+		} `json:"game"`
+		State   string   `json:"state"`
+		Players []string `json:"players"`
+	}
+	err := json.Unmarshal(result, &games)
+	if err != nil {
+		return fmt.Errorf("unable to decode games: %s", err.Error())
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].Game.Name < games[j].Game.Name })
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tTYPE\tSTATE\tPLAYERS\tPRIVATE\tHOST")
+	for _, g := range games {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%t\t%s\n",
+			g.Game.ID, g.Game.Name, g.Game.Type, g.State, len(g.Players), g.Game.MaxPlayers, g.Game.Private, g.Game.Host)
+	}
+	return w.Flush()
+}
+
+// printPlayers prints the players in a table.
+func printPlayers(result json.RawMessage) error {
+	var players []manager.AdminPlayer
+	err := json.Unmarshal(result, &players)
+	if err != nil {
+		return fmt.Errorf("unable to decode players: %s", err.Error())
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].Name < players[j].Name })
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tRATING\tGAMES\tCONNECTED\tBOT")
+	for _, p := range players {
+		fmt.Fprintf(w, "%s\t%s\t%.0f\t%d\t%t\t%t\n", p.ID, p.Name, p.Rating, p.Games, p.Connected, p.Bot)
+	}
+	return w.Flush()
+}
@@ -84,7 +84,7 @@ func main() {
 	}
 	log.Debug().Msgf("player %#v", player)
 
-	result, err = c.RPC(context.Background(), "createGame", []byte(`{"minPlayers": 1, "maxPlayers": 2}`))
+	result, err = c.RPC(context.Background(), "createGame", []byte(`{"minPlayers": 2, "maxPlayers": 2, "idPlayer": "`+player.ID.String()+`"}`))
 	if err != nil {
 		log.Panic().Msgf("error executing RPC: %s", err.Error())
 	}
@@ -123,8 +123,8 @@ func main() {
 		}
 		log.Info().Msgf("[%s] publication event: %#v", game.TopicName, data)
 
-		if data.Type == "rpc" && data.Data == "revealTwoCards" {
-			log.Info().Msgf("[%s] revealTwoCards publication event !", game.TopicName)
+		if data.Type == "rpc" && data.Data == games.RevealCards {
+			log.Info().Msgf("[%s] revealCards publication event !", game.TopicName)
 			wg.Done()
 		}
 	}
@@ -148,19 +148,30 @@ func main() {
 
 	wg.Add(1)
 	log.Debug().Msgf("START GAME %s", game.Name)
-	result, err = c.RPC(context.Background(), "startGame", []byte(`{"id": "`+game.ID.String()+`"}`))
+	result, err = c.RPC(context.Background(), "startGame", []byte(`{"id": "`+game.ID.String()+`", "idPlayer": "`+player.ID.String()+`"}`))
 	if err != nil {
 		log.Error().Msgf("error executing RPC: %s", err.Error())
 		return
 	}
 	log.Debug().Msgf("startGame result: %s", string(result.Data))
 
-	log.Debug().Msgf("waiting subscribe event revealTwoCards ...")
+	log.Debug().Msgf("waiting subscribe event revealCards ...")
 	wg.Wait()
-	log.Debug().Msgf("received subscribe event revealTwoCards")
+	log.Debug().Msgf("received subscribe event revealCards")
 
-	log.Debug().Msgf("wait 2s to call rpc playerInit")
+	log.Debug().Msgf("wait 2s to call rpc playMove")
 	time.Sleep(2 * time.Second)
+	log.Debug().Msgf("PLAYMOVE GAME %s", game.Name)
+	reveals := []games.Move{}
+	for i := 0; i < game.Options.InitialReveals; i++ {
+		reveals = append(reveals, games.Move{Kind: games.Reveal, Square: games.Square{Column: i % games.Columns, Row: i / games.Columns}})
+	}
+	result, err = c.RPC(context.Background(), "playMove", []byte(`{"idGame": "`+game.ID.String()+`", "idPlayer": "`+player.ID.String()+`", "move": "`+games.FormatMoves(reveals)+`"}`))
+	if err != nil {
+		log.Panic().Msgf("error executing RPC: %s", err.Error())
+	}
+	log.Debug().Msgf("playMove result: %s", string(result.Data))
+
 	log.Debug().Msgf("PLAYERINIT GAME %s", game.Name)
 	result, err = c.RPC(context.Background(), "playerInit", []byte(`{"idGame": "`+game.ID.String()+`", "idPlayer": "`+player.ID.String()+`"}`))
 	if err != nil {
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	centrifuge "github.com/centrifugal/centrifuge-go"
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// tail prints the server logs published on the admin logs channel until
+// interrupted. The subscription data must provide the admin token.
+func tail(log *zerolog.Logger, c *centrifuge.Client, data []byte) error {
+	output := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}
+
+	subscription, err := c.NewSubscription(utils.AdminLogsChannel, centrifuge.SubscriptionConfig{Data: data})
+	if err != nil {
+		return fmt.Errorf("new subscription to %s error: %s", utils.AdminLogsChannel, err.Error())
+	}
+
+	subscribed := make(chan error, 1)
+	subscription.OnSubscribed(func(e centrifuge.SubscribedEvent) {
+		subscribed <- nil
+	})
+	subscription.OnUnsubscribed(func(e centrifuge.UnsubscribedEvent) {
+		select {
+		case subscribed <- fmt.Errorf("unsubscribed from %s: %s", utils.AdminLogsChannel, e.Reason):
+		default:
+		}
+	})
+	subscription.OnPublication(func(e centrifuge.PublicationEvent) {
+		_, err := output.Write(e.Data)
+		if err != nil {
+			log.Error().Msgf("invalid log entry %s: %s", string(e.Data), err.Error())
+		}
+	})
+
+	err = subscription.Subscribe()
+	if err != nil {
+		return fmt.Errorf("subscription to %s error: %s", utils.AdminLogsChannel, err.Error())
+	}
+
+	err = <-subscribed
+	if err != nil {
+		return err
+	}
+	log.Info().Msg("tailing server logs, press Ctrl+C to stop")
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	<-interrupt
+
+	return subscription.Unsubscribe()
+}
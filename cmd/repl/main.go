@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"sync"
@@ -13,6 +14,11 @@ import (
 	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
 )
 
+type Response struct {
+	Status string `json:"status"`
+	Result string `json:"result"`
+}
+
 func main() {
 	var err error
 	var wg sync.WaitGroup
@@ -46,7 +52,12 @@ func main() {
 		return
 	}
 
-	rpc := replRun()
+	rpc, err := replRun()
+	if err != nil {
+		log.Error().Msgf("error: %s", err.Error())
+		return
+	}
+
 	validate := validator.New()
 	err = validate.Var(rpc.Payload, "json")
 	if err != nil {
@@ -54,11 +65,29 @@ func main() {
 		return
 	}
 
+	// server logs are tailed until interrupted
+	if rpc.Method == tailLogsMethod {
+		err = tail(&log, c, []byte(rpc.Payload))
+		if err != nil {
+			log.Error().Msgf("error tailing logs: %s", err.Error())
+		}
+		return
+	}
+
 	result, err := c.RPC(context.Background(), rpc.Method, []byte(rpc.Payload))
 	if err != nil {
 		log.Error().Msgf("error executing RPC: %s", err.Error())
 	}
 	log.Printf("RPC result: %s", string(result.Data))
 
+	// game logs are printed in the games notation
+	if rpc.Method == gameLogMethod {
+		var response Response
+		err = json.Unmarshal(result.Data, &response)
+		if err == nil && response.Status == "ok" {
+			fmt.Print(response.Result)
+		}
+	}
+
 	log.Info().Msg("exit")
 }
@@ -1,7 +1,17 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/pterm/pterm"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+const (
+	playMoveMethod string = "playMove"
+	gameLogMethod  string = "gameLog"
+	tailLogsMethod string = "tailLogs"
 )
 
 type RPC struct {
@@ -9,7 +19,7 @@ type RPC struct {
 	Payload string
 }
 
-func replRun() RPC {
+func replRun() (RPC, error) {
 	primary := pterm.NewStyle(pterm.FgLightCyan, pterm.Bold)
 	secondary := pterm.NewStyle(pterm.FgLightGreen, pterm.Italic)
 
@@ -17,6 +27,15 @@ func replRun() RPC {
 		WithMultiLine(false).
 		WithTextStyle(primary).
 		Show("method  ")
+
+	if method == playMoveMethod {
+		return replMove(secondary)
+	}
+
+	if method == tailLogsMethod {
+		return replTail(secondary)
+	}
+
 	payload, _ := pterm.DefaultInteractiveTextInput.
 		WithMultiLine(false).
 		WithTextStyle(secondary).
@@ -25,5 +44,47 @@ func replRun() RPC {
 	return RPC{
 		Method:  method,
 		Payload: payload,
+	}, nil
+}
+
+// replMove prompts for a move written in the games notation, eg "D7>b2",
+// and builds the matching playMove payload.
+func replMove(style *pterm.Style) (RPC, error) {
+	game, _ := pterm.DefaultInteractiveTextInput.
+		WithMultiLine(false).
+		WithTextStyle(style).
+		Show("game    ")
+	player, _ := pterm.DefaultInteractiveTextInput.
+		WithMultiLine(false).
+		WithTextStyle(style).
+		Show("player  ")
+	move, _ := pterm.DefaultInteractiveTextInput.
+		WithMultiLine(false).
+		WithTextStyle(style).
+		Show("move    ")
+
+	moves, err := games.ParseMoves(move)
+	if err != nil {
+		return RPC{}, err
 	}
+
+	return RPC{
+		Method:  playMoveMethod,
+		Payload: fmt.Sprintf(`{"idGame": %q, "idPlayer": %q, "move": %q}`, game, player, games.FormatMoves(moves)),
+	}, nil
+}
+
+// replTail prompts for the admin token and builds the payload of the
+// subscription to the server logs.
+func replTail(style *pterm.Style) (RPC, error) {
+	token, _ := pterm.DefaultInteractiveTextInput.
+		WithMultiLine(false).
+		WithMask("*").
+		WithTextStyle(style).
+		Show("token   ")
+
+	return RPC{
+		Method:  tailLogsMethod,
+		Payload: fmt.Sprintf(`{"token": %q}`, token),
+	}, nil
 }
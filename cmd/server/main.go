@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
@@ -9,35 +11,73 @@ import (
 
 	_ "github.com/mattn/go-sqlite3" // Import go-sqlite3 library
 
-	"github.com/rs/zerolog"
-
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
 	"github.com/jtbonhomme/gameserver-websocket/internal/storage/memory"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
 )
 
 func main() {
 	var err error
 
-	// Init logger
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	output := zerolog.ConsoleWriter{
-		Out:           os.Stderr,
-		TimeFormat:    time.RFC3339,
-		FormatMessage: func(i interface{}) string { return fmt.Sprintf("[main] %s", i) },
+	allowSeed := flag.Bool("allow-seed", false, "allow clients to provide the seed of the games they create (tests only)")
+	takeoverGrace := flag.Duration("takeover-grace", 0, "hand the seat of a player disconnected from a running game to a bot after this grace period (disabled if 0)")
+	adminToken := flag.String("admin-token", "", "token allowing admins to manage any game (no admin if empty)")
+	gameRetention := flag.Duration("game-retention", 10*time.Minute, "archive and remove the games which are over after this time")
+	lobbyExpiry := flag.Duration("lobby-expiry", 30*time.Minute, "close the lobbies of games not started after this time without activity (never if 0)")
+	logFormat := flag.String("log-format", logging.Console, "format of the logs, console or json")
+	logLevel := flag.String("log-level", "info", "default level of the logs")
+	logLevels := flag.String("log-levels", "", "levels of the logs of components, eg manager=debug,game=warn (components: main, manager, memory, game, bot)")
+	streamLevel := flag.String("log-stream-level", "info", "level of the logs streamed to the admins")
+	streamComponents := flag.String("log-stream-components", "", "components whose logs are streamed to the admins, eg manager,game (all if empty)")
+	traceExporter := flag.String("trace-exporter", tracing.None, "exporter of the traces, stdout or otlp (no tracing if empty)")
+	traceEndpoint := flag.String("trace-endpoint", "http://localhost:4318", "URL of the OTLP collector of the traces")
+	flag.Parse()
+
+	// Init logger, streaming the logs to the admins if any
+	var stream *logging.Stream
+	if *adminToken != "" {
+		stream, err = logging.NewStream(*streamLevel, *streamComponents, logging.DefaultStreamSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logging configuration error: %s\n", err.Error())
+			os.Exit(2)
+		}
+	}
+	root, err := logging.New(logging.Config{Format: *logFormat, Level: *logLevel, Levels: *logLevels, Stream: stream})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logging configuration error: %s\n", err.Error())
+		os.Exit(2)
 	}
-	logger := zerolog.New(output).With().Timestamp().Logger()
+	logger := logging.Component(&root, "main")
 
-	m := memory.New(&logger)
+	shutdownTracing, err := tracing.New(context.Background(), tracing.Config{Exporter: *traceExporter, Endpoint: *traceEndpoint})
+	if err != nil {
+		logger.Fatal().Msgf("tracing configuration error: %s", err.Error())
+	}
+	defer func() {
+		_ = shutdownTracing(context.Background())
+	}()
+
+	m := memory.New(&root)
 	logger.Info().Msg("start manager")
-	mgr := manager.New(&logger, m)
+	opts := []manager.Option{}
+	if *allowSeed {
+		opts = append(opts, manager.WithSeedAllowed())
+	}
+	if *takeoverGrace > 0 {
+		opts = append(opts, manager.WithBotTakeover(*takeoverGrace))
+	}
+	if *adminToken != "" {
+		opts = append(opts, manager.WithAdminToken(*adminToken), manager.WithLogStream(stream))
+	}
+	opts = append(opts, manager.WithLobbyExpiry(*lobbyExpiry, 10*time.Second))
+	opts = append(opts, manager.WithGameRetention(*gameRetention, time.Minute))
+	mgr := manager.New(&root, m, opts...)
 	err = mgr.Start()
 	if err != nil {
 		logger.Panic().Msgf("manager start error: %s", err.Error())
 	}
 
-	// todo: use websocket to send logs: provide an io.Writer implementing object
-	// todo: manager websocket availability (because it starts in a goroutine)
-
 	// Waiting signal
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/bots"
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// stats aggregates the results of simulated games by strategy.
+type stats struct {
+	games       int
+	seats       map[string]int
+	wins        map[string]int
+	roundScores map[string]int
+	rounds      map[string]int
+	lengths     map[int]int
+	turns       []int
+	failures    []error
+}
+
+func newStats() *stats {
+	return &stats{
+		seats:       make(map[string]int),
+		wins:        make(map[string]int),
+		roundScores: make(map[string]int),
+		rounds:      make(map[string]int),
+		lengths:     make(map[int]int),
+		turns:       []int{},
+		failures:    []error{},
+	}
+}
+
+// add aggregates the result of a simulated game.
+func (s *stats) add(result *bots.Result) {
+	s.games++
+	for _, pID := range result.Players {
+		strategy := result.Strategies[pID]
+		s.seats[strategy]++
+		if pID == result.Winner {
+			s.wins[strategy]++
+		}
+		for _, scores := range result.RoundScores {
+			s.roundScores[strategy] += scores[pID]
+			s.rounds[strategy]++
+		}
+	}
+	s.lengths[result.Rounds]++
+	s.turns = append(s.turns, result.Turns)
+}
+
+// print writes the aggregated statistics.
+func (s *stats) print(strategies []string) {
+	fmt.Printf("games: %d, failures: %d\n\n", s.games, len(s.failures))
+	if s.games == 0 {
+		return
+	}
+
+	names := []string{}
+	for _, name := range strategies {
+		if !utils.ContainsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	fmt.Printf("%-10s %8s %8s %10s\n", "strategy", "seats", "win rate", "avg round")
+	for _, name := range names {
+		winRate := float64(s.wins[name]) / float64(s.seats[name])
+		avgRound := float64(s.roundScores[name]) / float64(s.rounds[name])
+		fmt.Printf("%-10s %8d %7.1f%% %10.2f\n", name, s.seats[name], 100*winRate, avgRound)
+	}
+
+	fmt.Printf("\nrounds per game:\n")
+	lengths := []int{}
+	for n := range s.lengths {
+		lengths = append(lengths, n)
+	}
+	sort.Ints(lengths)
+	for _, n := range lengths {
+		share := float64(s.lengths[n]) / float64(s.games)
+		fmt.Printf("%4d %6d %s\n", n, s.lengths[n], strings.Repeat("#", int(50*share+0.5)))
+	}
+
+	sort.Ints(s.turns)
+	total := 0
+	for _, n := range s.turns {
+		total += n
+	}
+	fmt.Printf("\nturns per game: min %d, median %d, avg %.1f, max %d\n",
+		s.turns[0], s.turns[len(s.turns)/2], float64(total)/float64(len(s.turns)), s.turns[len(s.turns)-1])
+}
+
+func main() {
+	from := flag.Int64("seed", 1, "seed of the first simulated game")
+	n := flag.Int("n", 1000, "number of simulated games, with consecutive seeds")
+	players := flag.String("bots", bots.GreedyStrategy+","+bots.RandomStrategy, "comma separated strategies of the bots seated in each game, among "+strings.Join(bots.Strategies(), ", "))
+	rules := flag.String("options", "", "JSON options of the simulated games, eg '{\"columnClears\": false}', standard rules if empty")
+	workers := flag.Int("workers", runtime.NumCPU(), "number of games simulated concurrently")
+	verbose := flag.Bool("v", false, "log games and bots activity")
+	flag.Parse()
+
+	// Init logger
+	level := "error"
+	if *verbose {
+		level = "debug"
+	}
+	root, err := logging.New(logging.Config{Level: level})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logging configuration error: %s\n", err.Error())
+		os.Exit(2)
+	}
+	log := logging.Component(&root, "simulate")
+
+	strategies := strings.Split(*players, ",")
+	for _, name := range strategies {
+		if !utils.ContainsString(bots.Strategies(), name) {
+			log.Fatal().Msgf("unknown strategy %q", name)
+		}
+	}
+
+	options := games.DefaultOptions()
+	if *rules != "" {
+		err := json.Unmarshal([]byte(*rules), &options)
+		if err != nil {
+			log.Fatal().Msgf("invalid options %q: %s", *rules, err.Error())
+		}
+	}
+
+	err = options.Validate(len(strategies), len(strategies))
+	if err != nil {
+		log.Fatal().Msgf("invalid options: %s", err.Error())
+	}
+
+	seeds := make(chan int64)
+	results := make(chan *bots.Result)
+	failures := make(chan error)
+
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for seed := range seeds {
+				result, err := bots.Simulate(&root, seed, strategies, games.WithOptions(options))
+				if err != nil {
+					failures <- err
+					continue
+				}
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < *n; i++ {
+			seeds <- *from + int64(i)
+		}
+		close(seeds)
+		wg.Wait()
+		close(results)
+		close(failures)
+	}()
+
+	start := time.Now()
+	s := newStats()
+	for results != nil || failures != nil {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+			s.add(result)
+		case err, ok := <-failures:
+			if !ok {
+				failures = nil
+				continue
+			}
+			s.failures = append(s.failures, err)
+		}
+	}
+
+	log.Info().Msgf("simulated %d games in %s", *n, time.Since(start))
+	s.print(strategies)
+
+	if len(s.failures) > 0 {
+		fmt.Printf("\nfailures:\n")
+		for _, err := range s.failures {
+			fmt.Printf("  %s\n", err.Error())
+		}
+		os.Exit(1)
+	}
+}
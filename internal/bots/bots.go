@@ -0,0 +1,168 @@
+package bots
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+)
+
+// Bot is an in-process player seated in a game. It reveals its initial cards,
+// answers playerInit and plays its turns with a strategy, through the same
+// game methods as the RPCs of human players.
+type Bot struct {
+	log      *zerolog.Logger
+	ID       string
+	game     *games.Game
+	strategy Strategy
+	kick     chan struct{}
+	done     chan struct{}
+}
+
+// Supports returns an error if bots cannot play a game. The strategies of
+// bots play Skyjo games, on the view of their player.
+func Supports(game *games.Game) error {
+	if game.Type != games.Skyjo {
+		return fmt.Errorf("no bot plays %s games", game.Type)
+	}
+
+	return nil
+}
+
+// New creates a bot playing as the player with the given ID in a game.
+// The player must have joined the game.
+func New(l *zerolog.Logger, id string, game *games.Game, strategy Strategy) *Bot {
+	log := logging.Component(l, "bot").With().Str(logging.GameID, game.ID.String()).Str(logging.PlayerID, id).Logger()
+
+	return &Bot{
+		log:      &log,
+		ID:       id,
+		game:     game,
+		strategy: strategy,
+		kick:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+// Game returns the game the bot is seated in.
+func (b *Bot) Game() *games.Game {
+	return b.game
+}
+
+// Start makes the bot play each time the game publishes an event,
+// until the game ends or the bot is stopped.
+func (b *Bot) Start() {
+	b.game.OnEvent(func(e games.Event) {
+		b.wake()
+	})
+
+	go b.run()
+	b.wake()
+}
+
+// Stop stops the bot.
+func (b *Bot) Stop() {
+	select {
+	case <-b.done:
+	default:
+		close(b.done)
+	}
+}
+
+// wake notifies the bot that the game state changed. Notifications are
+// coalesced, as the bot reads the whole game state when it wakes up.
+func (b *Bot) wake() {
+	select {
+	case b.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (b *Bot) run() {
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-b.kick:
+			finished, err := b.Act()
+			if err != nil {
+				b.log.Error().Msgf("bot error: %s", err.Error())
+			}
+			if finished {
+				b.Stop()
+				return
+			}
+		}
+	}
+}
+
+// Act plays what the bot is expected to play in the current game state, if
+// anything, and returns true when the game is finished.
+func (b *Bot) Act() (bool, error) {
+	if b.game.IsFinished() {
+		return true, nil
+	}
+
+	if !b.game.IsStarted() {
+		return false, nil
+	}
+
+	v, err := b.game.View(b.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if v.Finished {
+		return true, nil
+	}
+
+	if !v.Playing && !v.Initialized {
+		return false, b.init(v)
+	}
+
+	if v.Playing && v.Current == b.ID {
+		return false, b.turn(v)
+	}
+
+	return false, nil
+}
+
+// init reveals the initial cards and answers playerInit.
+func (b *Bot) init(v games.View) error {
+	board := v.Boards[b.ID]
+	n := v.Options.InitialReveals - board.RevealedCount()
+	if n > 0 {
+		moves := []games.Move{}
+		for _, sq := range b.strategy.Reveal(v, n) {
+			moves = append(moves, games.Move{Kind: games.Reveal, Square: sq})
+		}
+
+		err := b.game.Play(b.ID, moves...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.game.PlayerInit(b.ID)
+}
+
+// turn plays a whole turn.
+func (b *Bot) turn(v games.View) error {
+	var err error
+
+	if !v.Holding {
+		err = b.game.Play(b.ID, games.Move{Kind: b.strategy.Pick(v)})
+		if err != nil {
+			return err
+		}
+
+		v, err = b.game.View(b.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.game.Play(b.ID, b.strategy.Place(v))
+}
@@ -0,0 +1,84 @@
+package bots_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/bots"
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestBots(t *testing.T) {
+	logger := zerolog.Nop()
+
+	game := games.New(&logger, 2, 3, games.WithSeed(1))
+
+	end := make(chan string, 1)
+	game.OnEvent(func(e games.Event) {
+		if e.Type == "end" {
+			end <- e.Data
+		}
+	})
+
+	for i, name := range []string{bots.RandomStrategy, bots.GreedyStrategy, bots.GreedyStrategy} {
+		strategy, err := bots.NewStrategy(name, rand.New(rand.NewSource(int64(i))))
+		if err != nil {
+			t.Fatalf("unexpected error creating strategy %s: %v", name, err)
+		}
+
+		id := name + string(rune('1'+i))
+		err = game.AddPlayer(id)
+		if err != nil {
+			t.Fatalf("unexpected error when adding a player: %v", err)
+		}
+
+		bot := bots.New(&logger, id, game, strategy)
+		bot.Start()
+		defer bot.Stop()
+	}
+
+	err := game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error when starting the game: %v", err)
+	}
+
+	select {
+	case winner := <-end:
+		if winner == "" {
+			t.Errorf("expected a winner")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("game not finished by bots, log:\n%s", game.Log())
+	}
+
+	if !game.IsFinished() {
+		t.Errorf("expected game to be finished")
+	}
+
+	over := false
+	for _, score := range game.Scores() {
+		if score >= games.ScoreLimit {
+			over = true
+		}
+	}
+	if !over {
+		t.Errorf("expected a player to reach the score limit, got %v", game.Scores())
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	for _, name := range bots.Strategies() {
+		_, err := bots.NewStrategy(name, rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Errorf("unexpected error creating strategy %s: %v", name, err)
+		}
+	}
+
+	_, err := bots.NewStrategy("fake", rand.New(rand.NewSource(1)))
+	if err == nil {
+		t.Errorf("expected error creating unknown strategy")
+	}
+}
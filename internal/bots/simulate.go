@@ -0,0 +1,137 @@
+package bots
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+// MaxSimulationSteps bounds the number of bot passes of a simulated game,
+// so that a rules engine bug can not make a simulation loop forever.
+const MaxSimulationSteps int = 100000
+
+// Result is the outcome of a simulated game.
+type Result struct {
+	Seed        int64
+	Players     []string
+	Strategies  map[string]string
+	Winner      string
+	Scores      map[string]int
+	RoundScores []map[string]int
+	Rounds      int
+	Turns       int
+	Log         games.Log
+}
+
+// Simulate plays a complete game between bots in-process, without any
+// websocket connection. The game is created with the given seed, and each
+// strategy plays one seat. Seats are rotated with the seed, so that a range
+// of seeds does not always give the first seat to the same strategy. Game
+// invariants are checked after each bot action, and an error is returned
+// when one is violated or when the bots are stalled. Game options may be
+// provided to simulate variants.
+func Simulate(l *zerolog.Logger, seed int64, strategies []string, opts ...games.Option) (*Result, error) {
+	n := len(strategies)
+	if n == 0 {
+		return nil, fmt.Errorf("no strategy to simulate")
+	}
+
+	game := games.New(l, n, n, append([]games.Option{games.WithSeed(seed)}, opts...)...)
+
+	result := &Result{
+		Seed:        seed,
+		Players:     []string{},
+		Strategies:  make(map[string]string),
+		RoundScores: []map[string]int{},
+	}
+
+	offset := int(seed % int64(n))
+	if offset < 0 {
+		offset += n
+	}
+
+	players := []*Bot{}
+	for i := 0; i < n; i++ {
+		name := strategies[(i+offset)%n]
+		strategy, err := NewStrategy(name, rand.New(rand.NewSource(seed+int64(i))))
+		if err != nil {
+			return nil, err
+		}
+
+		id := fmt.Sprintf("%s-%d", name, i+1)
+		err = game.AddPlayer(id)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Players = append(result.Players, id)
+		result.Strategies[id] = name
+		players = append(players, New(l, id, game, strategy))
+	}
+
+	// events are counted to detect stalled games, and handlers run in the
+	// goroutine of the acting bot, so that no lock is needed.
+	events := 0
+	var roundErr error
+	game.OnEvent(func(e games.Event) {
+		events++
+		switch e.Type {
+		case "turn":
+			result.Turns++
+		case "round":
+			scores := make(map[string]int)
+			err := json.Unmarshal([]byte(e.Data), &scores)
+			if err != nil {
+				roundErr = err
+			}
+			result.RoundScores = append(result.RoundScores, scores)
+		case "end":
+			result.Winner = e.Data
+		}
+	})
+
+	err := game.Start()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = game.Stop()
+	}()
+
+	for step := 0; !game.IsFinished(); step++ {
+		if step == MaxSimulationSteps {
+			return result, fmt.Errorf("seed %d: [%s] game not finished after %d steps", seed, game.Name, step)
+		}
+
+		before := events
+		for _, bot := range players {
+			_, err = bot.Act()
+			if err != nil {
+				return result, fmt.Errorf("seed %d: %s", seed, err.Error())
+			}
+
+			err = game.CheckInvariants()
+			if err != nil {
+				return result, fmt.Errorf("seed %d: %s", seed, err.Error())
+			}
+		}
+
+		if roundErr != nil {
+			return result, fmt.Errorf("seed %d: unable to read round scores: %s", seed, roundErr.Error())
+		}
+
+		if events == before {
+			return result, fmt.Errorf("seed %d: [%s] bots stalled", seed, game.Name)
+		}
+	}
+
+	result.Rounds = len(result.RoundScores)
+	result.Scores = game.Scores()
+	result.Log = game.Log()
+
+	return result, nil
+}
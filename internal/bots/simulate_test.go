@@ -0,0 +1,71 @@
+package bots_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/bots"
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestSimulate(t *testing.T) {
+	logger := zerolog.Nop()
+	strategies := []string{bots.GreedyStrategy, bots.RandomStrategy, bots.GreedyStrategy}
+
+	for seed := int64(-5); seed < 20; seed++ {
+		result, err := bots.Simulate(&logger, seed, strategies)
+		if err != nil {
+			t.Fatalf("unexpected error simulating seed %d: %v", seed, err)
+		}
+
+		if result.Winner == "" {
+			t.Errorf("seed %d: expected a winner", seed)
+		}
+
+		if result.Rounds == 0 || result.Rounds != len(result.RoundScores) {
+			t.Errorf("seed %d: unexpected rounds %d for %d round scores", seed, result.Rounds, len(result.RoundScores))
+		}
+
+		total := make(map[string]int)
+		for _, scores := range result.RoundScores {
+			for pID, s := range scores {
+				total[pID] += s
+			}
+		}
+		for _, pID := range result.Players {
+			if total[pID] != result.Scores[pID] {
+				t.Errorf("seed %d: expected player %s score %d, got %d", seed, pID, total[pID], result.Scores[pID])
+			}
+		}
+
+		// a simulated game is replayed identically from its log,
+		// except with seed 0 which is not recorded in logs.
+		if seed == 0 {
+			continue
+		}
+		game, err := games.Replay(&logger, &result.Log)
+		if err != nil {
+			t.Fatalf("seed %d: unexpected error replaying the game: %v", seed, err)
+		}
+		if !game.IsFinished() {
+			t.Errorf("seed %d: expected replayed game to be finished", seed)
+		}
+	}
+
+	_, err := bots.Simulate(&logger, 1, []string{"fake"})
+	if err == nil {
+		t.Errorf("expected an error simulating an unknown strategy")
+	}
+
+	options := games.DefaultOptions()
+	options.ColumnClears = false
+	options.DoublePenalty = false
+	options.ScoreLimit = 40
+	for seed := int64(1); seed < 10; seed++ {
+		_, err = bots.Simulate(&logger, seed, strategies, games.WithOptions(options))
+		if err != nil {
+			t.Fatalf("unexpected error simulating seed %d with options: %v", seed, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package bots
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+const (
+	RandomStrategy string = "random"
+	GreedyStrategy string = "greedy"
+)
+
+// Strategy decides the moves of a bot from the game state it can see.
+type Strategy interface {
+	// Reveal returns the squares to reveal at the beginning of a round.
+	Reveal(v games.View, n int) []games.Square
+	// Pick returns Draw to draw from the draw pile, or Take to take the top
+	// card of the discard pile.
+	Pick(v games.View) games.MoveKind
+	// Place returns the move ending the turn once a card is held: a Place
+	// move, or a Discard move if the held card was drawn from the draw pile.
+	Place(v games.View) games.Move
+}
+
+// Strategies returns the names of the available strategies.
+func Strategies() []string {
+	return []string{RandomStrategy, GreedyStrategy}
+}
+
+// NewStrategy returns the strategy with the given name, using a random source
+// for its random choices.
+func NewStrategy(name string, r *rand.Rand) (Strategy, error) {
+	switch name {
+	case RandomStrategy:
+		return &Random{rand: r}, nil
+	case GreedyStrategy:
+		return &Greedy{rand: r}, nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %q", name)
+	}
+}
+
+// Random plays random legal moves.
+type Random struct {
+	rand *rand.Rand
+}
+
+// Reveal returns random face-down squares.
+func (s *Random) Reveal(v games.View, n int) []games.Square {
+	hidden := squares(v.Boards[v.Player], false)
+	s.rand.Shuffle(len(hidden), func(i, j int) {
+		hidden[i], hidden[j] = hidden[j], hidden[i]
+	})
+
+	if len(hidden) < n {
+		n = len(hidden)
+	}
+
+	return hidden[:n]
+}
+
+// Pick draws or takes randomly.
+func (s *Random) Pick(v games.View) games.MoveKind {
+	if v.HasDiscard && s.rand.Intn(2) == 0 {
+		return games.Take
+	}
+
+	return games.Draw
+}
+
+// Place places the held card on a random square, or discards it
+// and reveals a random face-down square.
+func (s *Random) Place(v games.View) games.Move {
+	board := v.Boards[v.Player]
+	hidden := squares(board, false)
+	if v.HeldFromDeck && len(hidden) > 0 && s.rand.Intn(2) == 0 {
+		return games.Move{Kind: games.Discard, Square: hidden[s.rand.Intn(len(hidden))]}
+	}
+
+	all := append(hidden, squares(board, true)...)
+	return games.Move{Kind: games.Place, Square: all[s.rand.Intn(len(all))]}
+}
+
+// Greedy lowers its revealed cards as fast as possible: it replaces its highest
+// revealed card with any lower card, completes columns of identical cards,
+// and keeps low cards to replace face-down ones.
+type Greedy struct {
+	rand *rand.Rand
+}
+
+// greedyLowCard is the highest card a greedy bot keeps to replace a face-down card.
+const greedyLowCard games.Card = 3
+
+// Reveal returns face-down squares of distinct columns.
+func (s *Greedy) Reveal(v games.View, n int) []games.Square {
+	hidden := squares(v.Boards[v.Player], false)
+	sort.SliceStable(hidden, func(i, j int) bool {
+		return hidden[i].Row < hidden[j].Row
+	})
+
+	if len(hidden) < n {
+		n = len(hidden)
+	}
+
+	return hidden[:n]
+}
+
+// Pick takes the discarded card when it is worth placing.
+func (s *Greedy) Pick(v games.View) games.MoveKind {
+	if v.HasDiscard {
+		if _, ok := s.target(v, v.Discard); ok {
+			return games.Take
+		}
+	}
+
+	return games.Draw
+}
+
+// Place places the held card where it lowers the score the most, or
+// discards it and reveals a face-down card.
+func (s *Greedy) Place(v games.View) games.Move {
+	board := v.Boards[v.Player]
+
+	sq, ok := s.target(v, v.Held)
+	if ok {
+		return games.Move{Kind: games.Place, Square: sq}
+	}
+
+	hidden := squares(board, false)
+	if len(hidden) > 0 {
+		if v.HeldFromDeck {
+			return games.Move{Kind: games.Discard, Square: hidden[s.rand.Intn(len(hidden))]}
+		}
+		return games.Move{Kind: games.Place, Square: hidden[s.rand.Intn(len(hidden))]}
+	}
+
+	// no face-down card left: replace the highest card
+	sq, _ = highest(board)
+	return games.Move{Kind: games.Place, Square: sq}
+}
+
+// target returns the best square to place a card on, if any is worth it.
+func (s *Greedy) target(v games.View, card games.Card) (games.Square, bool) {
+	board := v.Boards[v.Player]
+
+	// complete a column whose other revealed cards all match the card
+	for c := 0; c < games.Columns && v.Options.ColumnClears; c++ {
+		matches, other := 0, games.Square{Column: -1}
+		for r := 0; r < games.Rows; r++ {
+			slot := board.Slots[c][r]
+			if slot.Cleared {
+				break
+			}
+			if slot.Revealed && slot.Card == card {
+				matches++
+			} else {
+				other = games.Square{Column: c, Row: r}
+			}
+		}
+		if matches == games.Rows-1 && other.Column >= 0 {
+			return other, true
+		}
+	}
+
+	// replace the highest revealed card
+	sq, ok := highest(board)
+	if ok && board.Slots[sq.Column][sq.Row].Card > card {
+		return sq, true
+	}
+
+	// replace a face-down card with a low card
+	hidden := squares(board, false)
+	if card <= greedyLowCard && len(hidden) > 0 {
+		return hidden[s.rand.Intn(len(hidden))], true
+	}
+
+	return games.Square{}, false
+}
+
+// squares returns the squares of a board still in play, revealed or not.
+func squares(board games.Board, revealed bool) []games.Square {
+	result := []games.Square{}
+	for c := 0; c < games.Columns; c++ {
+		for r := 0; r < games.Rows; r++ {
+			slot := board.Slots[c][r]
+			if !slot.Cleared && slot.Revealed == revealed {
+				result = append(result, games.Square{Column: c, Row: r})
+			}
+		}
+	}
+
+	return result
+}
+
+// highest returns the square of the highest revealed card of a board.
+func highest(board games.Board) (games.Square, bool) {
+	var best games.Square
+	found := false
+	for _, sq := range squares(board, true) {
+		if !found || board.Slots[sq.Column][sq.Row].Card > board.Slots[best.Column][best.Row].Card {
+			best, found = sq, true
+		}
+	}
+
+	return best, found
+}
@@ -0,0 +1,61 @@
+package games
+
+import (
+	"time"
+)
+
+// Archive is the record of a game which is over, kept once the game is
+// removed from the live games.
+type Archive struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	Players   []string       `json:"players"`
+	Scores    map[string]int `json:"scores"`
+	Ranking   [][]string     `json:"ranking"`
+	Aborted   bool           `json:"aborted"`
+	StartTime time.Time      `json:"startTime"`
+	EndTime   time.Time      `json:"endTime"`
+	Log       string         `json:"log"`
+}
+
+// IsOver returns true when the game is finished, or was stopped before
+// its end, ie aborted.
+func (game *Game) IsOver() bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.finished || (!game.started && !game.endTime.IsZero())
+}
+
+// EndTime returns the time the game finished or was stopped, or the zero
+// time if the game is not over.
+func (game *Game) EndTime() time.Time {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.endTime
+}
+
+// Archive returns the record of the game, including its whole log.
+func (game *Game) Archive() Archive {
+	scores := game.Scores()
+	ranking := game.Ranking()
+	log := game.Log()
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return Archive{
+		ID:        game.ID.String(),
+		Name:      game.Name,
+		Type:      game.Type,
+		Players:   append([]string{}, game.players...),
+		Scores:    scores,
+		Ranking:   ranking,
+		Aborted:   !game.finished,
+		StartTime: game.startTime,
+		EndTime:   game.endTime,
+		Log:       log.String(),
+	}
+}
@@ -0,0 +1,68 @@
+package games_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestArchive(t *testing.T) {
+	logger := zerolog.Nop()
+
+	// a stopped game is aborted
+	aborted := games.New(&logger, 1, 2)
+	_ = aborted.AddPlayer("p1")
+	if aborted.IsOver() {
+		t.Errorf("expected lobby not to be over")
+	}
+
+	err := aborted.Start()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if aborted.IsOver() || !aborted.EndTime().IsZero() {
+		t.Errorf("expected running game not to be over")
+	}
+
+	err = aborted.Stop()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !aborted.IsOver() || aborted.EndTime().IsZero() {
+		t.Errorf("expected stopped game to be over")
+	}
+	if !aborted.Archive().Aborted {
+		t.Errorf("expected stopped game to be archived as aborted")
+	}
+
+	// a game whose last opponent forfeited is finished
+	game := games.New(&logger, 2, 2, games.WithSeed(3))
+	_ = game.AddPlayer("p1")
+	_ = game.AddPlayer("p2")
+	err = game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	err = game.Forfeit("p2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !game.IsOver() {
+		t.Errorf("expected finished game to be over")
+	}
+
+	archive := game.Archive()
+	if archive.Aborted || archive.ID != game.ID.String() || len(archive.Players) != 2 {
+		t.Errorf("expected finished game archived, got %+v", archive)
+	}
+	if len(archive.Ranking) != 2 || archive.Ranking[0][0] != "p1" {
+		t.Errorf("expected p1 to rank first, got %v", archive.Ranking)
+	}
+	if !strings.Contains(archive.Log, `[Seed "3"]`) || !strings.Contains(archive.Log, "p2 F") {
+		t.Errorf("expected whole log with its seed and the forfeit, got %q", archive.Log)
+	}
+}
@@ -0,0 +1,149 @@
+package games
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	Rows    int = 3
+	Columns int = 4
+)
+
+// Square identifies a card location on a player's board. Columns are
+// noted from 'a' to 'd' and rows from 1 to 3, so that Square{0, 0} is "a1".
+type Square struct {
+	Column int
+	Row    int
+}
+
+// String returns the notation of the square, eg "b2".
+func (s Square) String() string {
+	return string(rune('a'+s.Column)) + strconv.Itoa(s.Row+1)
+}
+
+// Valid returns true if the square is inside the board.
+func (s Square) Valid() bool {
+	return s.Column >= 0 && s.Column < Columns && s.Row >= 0 && s.Row < Rows
+}
+
+// Slot is a card location on a board.
+type Slot struct {
+	Card     Card `json:"card"`
+	Revealed bool `json:"revealed"`
+	Cleared  bool `json:"cleared"`
+}
+
+// Board holds the cards dealt to a player.
+type Board struct {
+	Slots [Columns][Rows]Slot `json:"slots"`
+}
+
+// slot returns the slot at the given square.
+func (b *Board) slot(s Square) (*Slot, error) {
+	if !s.Valid() {
+		return nil, fmt.Errorf("invalid square %s", s)
+	}
+
+	slot := &b.Slots[s.Column][s.Row]
+	if slot.Cleared {
+		return nil, fmt.Errorf("square %s belongs to a cleared column", s)
+	}
+
+	return slot, nil
+}
+
+// RevealedCount returns the number of revealed cards still on the board.
+func (b *Board) RevealedCount() int {
+	n := 0
+	for c := 0; c < Columns; c++ {
+		for r := 0; r < Rows; r++ {
+			if b.Slots[c][r].Revealed && !b.Slots[c][r].Cleared {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// RevealedSum returns the sum of revealed cards still on the board.
+func (b *Board) RevealedSum() int {
+	sum := 0
+	for c := 0; c < Columns; c++ {
+		for r := 0; r < Rows; r++ {
+			if b.Slots[c][r].Revealed && !b.Slots[c][r].Cleared {
+				sum += int(b.Slots[c][r].Card)
+			}
+		}
+	}
+
+	return sum
+}
+
+// AllRevealed returns true when no face-down card is left on the board.
+func (b *Board) AllRevealed() bool {
+	for c := 0; c < Columns; c++ {
+		for r := 0; r < Rows; r++ {
+			if !b.Slots[c][r].Revealed && !b.Slots[c][r].Cleared {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Score returns the sum of all cards still on the board, revealed or not.
+func (b *Board) Score() int {
+	sum := 0
+	for c := 0; c < Columns; c++ {
+		for r := 0; r < Rows; r++ {
+			if !b.Slots[c][r].Cleared {
+				sum += int(b.Slots[c][r].Card)
+			}
+		}
+	}
+
+	return sum
+}
+
+// revealAll reveals every face-down card of the board.
+func (b *Board) revealAll() {
+	for c := 0; c < Columns; c++ {
+		for r := 0; r < Rows; r++ {
+			b.Slots[c][r].Revealed = true
+		}
+	}
+}
+
+// clearColumns removes every column made of identical revealed cards,
+// and returns the matching column clear moves.
+func (b *Board) clearColumns() []Move {
+	moves := []Move{}
+	for c := 0; c < Columns; c++ {
+		column := b.Slots[c]
+		if column[0].Cleared {
+			continue
+		}
+
+		same := true
+		for r := 0; r < Rows; r++ {
+			if !column[r].Revealed || column[r].Card != column[0].Card {
+				same = false
+				break
+			}
+		}
+
+		if !same {
+			continue
+		}
+
+		for r := 0; r < Rows; r++ {
+			b.Slots[c][r].Cleared = true
+		}
+		moves = append(moves, Move{Kind: Clear, Square: Square{Column: c}, Card: column[0].Card, Known: true})
+	}
+
+	return moves
+}
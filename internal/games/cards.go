@@ -0,0 +1,40 @@
+package games
+
+import (
+	"math/rand"
+)
+
+// Card represents the value of a Skyjo card.
+type Card int
+
+const (
+	MinCard Card = -2
+	MaxCard Card = 12
+)
+
+// StandardDeck returns the 150 cards of a standard Skyjo deck, not shuffled:
+// five -2, ten -1, fifteen 0, and ten of each card from 1 to 12.
+func StandardDeck() []Card {
+	deck := []Card{}
+	for c := MinCard; c <= MaxCard; c++ {
+		n := 10
+		switch c {
+		case -2:
+			n = 5
+		case 0:
+			n = 15
+		}
+		for i := 0; i < n; i++ {
+			deck = append(deck, c)
+		}
+	}
+
+	return deck
+}
+
+// shuffle shuffles cards in place.
+func shuffle(r *rand.Rand, cards []Card) {
+	r.Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+}
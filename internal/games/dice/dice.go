@@ -0,0 +1,219 @@
+// Package dice implements Pig, a simple dice game used to test the hosting
+// of games other than Skyjo. On their turn, players roll a die as many times
+// as they want, adding the rolls to their turn total, until they hold and
+// bank the turn total, or roll a 1 and lose it. The first player whose
+// score reaches the score limit of the game options wins.
+package dice
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+const (
+	// Type is the type of dice games.
+	Type string = "dice"
+	// Roll rolls the die.
+	Roll string = "roll"
+	// Hold banks the turn total and ends the turn.
+	Hold string = "hold"
+)
+
+func init() {
+	games.Register(Type, Ruleset{})
+}
+
+// State is the state of a dice game. Nothing is hidden to players.
+type State struct {
+	Players   []string       `json:"players"`
+	Scores    map[string]int `json:"scores"`
+	Current   string         `json:"current"`
+	TurnTotal int            `json:"turnTotal"`
+	LastRoll  int            `json:"lastRoll"`
+	Winner    string         `json:"winner"`
+	Forfeits  []string       `json:"forfeits"`
+	limit     int
+	current   int
+	rand      *rand.Rand
+}
+
+// Ruleset is the dice game ruleset.
+type Ruleset struct{}
+
+// Setup gives the first turn to the first player.
+func (Ruleset) Setup(game *games.Game) (games.State, []games.Event, error) {
+	players := append([]string{}, game.Players()...)
+	if len(players) == 0 {
+		return nil, nil, fmt.Errorf("no player")
+	}
+
+	s := &State{
+		Players:  players,
+		Scores:   make(map[string]int),
+		Forfeits: []string{},
+		Current:  players[0],
+		limit:    game.Options.ScoreLimit,
+		rand:     rand.New(rand.NewSource(game.Seed())),
+	}
+	for _, pID := range players {
+		s.Scores[pID] = 0
+	}
+
+	return s, []games.Event{{Type: "turn", Data: s.Current}}, nil
+}
+
+// Legal returns the moves of the current player.
+func (Ruleset) Legal(gs games.State, pID string) []string {
+	s := gs.(*State)
+	if s.Winner != "" || s.Current != pID {
+		return []string{}
+	}
+
+	if s.TurnTotal == 0 {
+		return []string{Roll}
+	}
+
+	return []string{Roll, Hold}
+}
+
+// Apply rolls the die or holds.
+func (r Ruleset) Apply(gs games.State, pID string, move string) ([]games.Event, error) {
+	s := gs.(*State)
+	if s.Winner != "" {
+		return nil, fmt.Errorf("game over")
+	}
+
+	if s.Current != pID {
+		return nil, fmt.Errorf("not player %s turn", pID)
+	}
+
+	legal := false
+	for _, m := range r.Legal(s, pID) {
+		legal = legal || m == move
+	}
+	if !legal {
+		return nil, fmt.Errorf("invalid move %q", move)
+	}
+
+	events := []games.Event{}
+	switch move {
+	case Roll:
+		s.LastRoll = 1 + s.rand.Intn(6)
+		events = append(events, games.Event{Type: "move", Player: pID, Data: Roll + " " + strconv.Itoa(s.LastRoll)})
+		if s.LastRoll != 1 {
+			s.TurnTotal += s.LastRoll
+			return events, nil
+		}
+		s.TurnTotal = 0
+	case Hold:
+		s.Scores[pID] += s.TurnTotal
+		s.TurnTotal = 0
+		events = append(events, games.Event{Type: "move", Player: pID, Data: Hold})
+		if s.Scores[pID] >= s.limit {
+			s.Winner = pID
+			return append(events, games.Event{Type: "end", Data: pID}), nil
+		}
+	}
+
+	return append(events, s.next()), nil
+}
+
+// Forfeit removes a player from the turns, and passes the turn if it
+// was the player's one. The last player left wins, the game publishing
+// the end event.
+func (Ruleset) Forfeit(gs games.State, pID string) ([]games.Event, error) {
+	s := gs.(*State)
+	if !utils.ContainsString(s.Players, pID) {
+		return nil, fmt.Errorf("unknown player %s", pID)
+	}
+
+	if utils.ContainsString(s.Forfeits, pID) {
+		return []games.Event{}, nil
+	}
+	s.Forfeits = append(s.Forfeits, pID)
+
+	left := []string{}
+	for _, p := range s.Players {
+		if !utils.ContainsString(s.Forfeits, p) {
+			left = append(left, p)
+		}
+	}
+	if s.Winner == "" && len(left) == 1 {
+		s.Winner = left[0]
+	}
+
+	if s.Winner != "" || s.Current != pID {
+		return []games.Event{}, nil
+	}
+
+	s.TurnTotal = 0
+
+	return []games.Event{s.next()}, nil
+}
+
+// next passes the turn to the next player who did not forfeit.
+func (s *State) next() games.Event {
+	for {
+		s.current = (s.current + 1) % len(s.Players)
+		if !utils.ContainsString(s.Forfeits, s.Players[s.current]) {
+			break
+		}
+	}
+	s.Current = s.Players[s.current]
+
+	return games.Event{Type: "turn", Data: s.Current}
+}
+
+// Score returns the banked scores of the players.
+func (Ruleset) Score(gs games.State) (map[string]int, bool) {
+	s := gs.(*State)
+
+	scores := make(map[string]int)
+	for pID, score := range s.Scores {
+		scores[pID] = score
+	}
+
+	return scores, s.Winner != ""
+}
+
+// Turn returns the current player. Turns have no timeout.
+func (Ruleset) Turn(gs games.State) (string, time.Time) {
+	s := gs.(*State)
+	if s.Winner != "" {
+		return "", time.Time{}
+	}
+
+	return s.Current, time.Time{}
+}
+
+// Rank ranks the players from the highest to the lowest total score.
+func (Ruleset) Rank(gs games.State) [][]string {
+	s := gs.(*State)
+
+	return games.RankByScore(s.Players, s.Scores, false)
+}
+
+// Redact returns the public state of the game, as nothing is hidden to players.
+func (r Ruleset) Redact(gs games.State, pID string) (interface{}, error) {
+	s := gs.(*State)
+	if !utils.ContainsString(s.Players, pID) {
+		return nil, fmt.Errorf("unknown player %s", pID)
+	}
+
+	scores, _ := r.Score(s)
+
+	return State{
+		Players:   append([]string{}, s.Players...),
+		Scores:    scores,
+		Current:   s.Current,
+		TurnTotal: s.TurnTotal,
+		LastRoll:  s.LastRoll,
+		Winner:    s.Winner,
+		Forfeits:  append([]string{}, s.Forfeits...),
+	}, nil
+}
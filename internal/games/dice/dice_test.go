@@ -0,0 +1,182 @@
+package dice_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/games/dice"
+)
+
+func TestDice(t *testing.T) {
+	logger := zerolog.Nop()
+
+	options := games.DefaultOptions()
+	options.ScoreLimit = 30
+	game := games.New(&logger, 2, 2, games.WithType(dice.Type), games.WithSeed(3), games.WithOptions(options))
+
+	end := make(chan string, 1)
+	game.OnEvent(func(e games.Event) {
+		if e.Type == "end" {
+			end <- e.Data
+		}
+	})
+
+	for _, pID := range []string{"p1", "p2"} {
+		err := game.AddPlayer(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when adding a player: %v", err)
+		}
+	}
+
+	err := game.Apply("p1", dice.Roll)
+	if err == nil {
+		t.Errorf("expected an error playing a game not started")
+	}
+
+	err = game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error when starting the game: %v", err)
+	}
+
+	err = game.Apply("p2", dice.Roll)
+	if err == nil {
+		t.Errorf("expected an error playing out of turn")
+	}
+
+	err = game.Apply("p1", dice.Hold)
+	if err == nil {
+		t.Errorf("expected an error holding before rolling")
+	}
+
+	err = game.Play("p1", games.Move{Kind: games.Draw})
+	if err == nil {
+		t.Errorf("expected an error playing Skyjo moves in a dice game")
+	}
+
+	// players roll until their turn total reaches 10
+	for i := 0; i < 1000 && !game.IsFinished(); i++ {
+		for _, pID := range []string{"p1", "p2"} {
+			legal := game.Legal(pID)
+			if len(legal) == 0 {
+				continue
+			}
+
+			v, err := game.State(pID)
+			if err != nil {
+				t.Fatalf("unexpected error getting the game state: %v", err)
+			}
+
+			move := dice.Roll
+			if v.(dice.State).TurnTotal >= 10 {
+				move = dice.Hold
+			}
+
+			err = game.Apply(pID, move)
+			if err != nil {
+				t.Fatalf("unexpected error playing %s: %v", move, err)
+			}
+		}
+	}
+
+	if !game.IsFinished() {
+		t.Fatalf("expected game to be finished")
+	}
+
+	winner := <-end
+	if game.Scores()[winner] < options.ScoreLimit {
+		t.Errorf("expected winner %s to reach the score limit, got %v", winner, game.Scores())
+	}
+
+	_, err = game.State("p3")
+	if err == nil {
+		t.Errorf("expected an error getting the game state of an unknown player")
+	}
+}
+
+func TestDiceForfeit(t *testing.T) {
+	logger := zerolog.Nop()
+
+	game := games.New(&logger, 3, 3, games.WithType(dice.Type), games.WithSeed(5))
+
+	turns := []string{}
+	end := make(chan string, 1)
+	game.OnEvent(func(e games.Event) {
+		switch e.Type {
+		case "turn":
+			turns = append(turns, e.Data)
+		case "end":
+			end <- e.Data
+		}
+	})
+
+	for _, pID := range []string{"p1", "p2", "p3"} {
+		_ = game.AddPlayer(pID)
+	}
+
+	err := game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error when starting the game: %v", err)
+	}
+
+	// the current player forfeits, the turn is passed
+	err = game.Forfeit("p1")
+	if err != nil {
+		t.Fatalf("unexpected error when forfeiting: %v", err)
+	}
+
+	if turns[len(turns)-1] != "p2" {
+		t.Errorf("expected turn of p2, got %v", turns)
+	}
+
+	err = game.Apply("p1", dice.Roll)
+	if err == nil {
+		t.Errorf("expected an error playing after a forfeit")
+	}
+
+	err = game.Apply("p2", dice.Roll)
+	if err != nil {
+		t.Fatalf("unexpected error playing: %v", err)
+	}
+	// p2 holds, unless a 1 was rolled
+	if len(game.Legal("p2")) == 2 {
+		err = game.Apply("p2", dice.Hold)
+		if err != nil {
+			t.Fatalf("unexpected error playing: %v", err)
+		}
+	}
+
+	if turns[len(turns)-1] != "p3" {
+		t.Errorf("expected forfeited player p1 to be skipped, got %v", turns)
+	}
+
+	// the last player left wins
+	err = game.Forfeit("p3")
+	if err != nil {
+		t.Fatalf("unexpected error when forfeiting: %v", err)
+	}
+
+	if !game.IsFinished() {
+		t.Fatalf("expected game to be finished")
+	}
+
+	winner := <-end
+	if winner != "p2" {
+		t.Errorf("expected p2 to win, got %q", winner)
+	}
+
+	v, err := game.State("p2")
+	if err != nil {
+		t.Fatalf("unexpected error getting the game state: %v", err)
+	}
+	if v.(dice.State).Winner != "p2" || len(v.(dice.State).Forfeits) != 2 {
+		t.Errorf("expected p2 to win after 2 forfeits, got %+v", v)
+	}
+
+	// players who forfeited rank last, whatever their score
+	ranking := game.Ranking()
+	if len(ranking) != 2 || len(ranking[0]) != 1 || ranking[0][0] != "p2" || len(ranking[1]) != 2 {
+		t.Errorf("expected p2 to rank first, and forfeited players to be tied last, got %v", ranking)
+	}
+}
@@ -0,0 +1,142 @@
+package games
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// Forfeit makes a player leave a running game. The player can no longer win,
+// and its turns are skipped unless its seat is controlled by a bot. The game
+// ends when a single player has not forfeited, who wins the game. Games whose
+// ruleset cannot skip turns end without winner when a player forfeits.
+func (game *Game) Forfeit(pID string) error {
+	game.stateMu.Lock()
+
+	game.mu.Lock()
+	if !game.started || game.finished || game.state == nil {
+		game.mu.Unlock()
+		game.stateMu.Unlock()
+		return fmt.Errorf("[%s] game not running", game.Name)
+	}
+
+	if !utils.ContainsString(game.players, pID) {
+		game.mu.Unlock()
+		game.stateMu.Unlock()
+		return fmt.Errorf("[%s] unknown player %s", game.Name, pID)
+	}
+
+	if game.forfeits[pID] {
+		game.mu.Unlock()
+		game.stateMu.Unlock()
+		return fmt.Errorf("[%s] player %s already forfeited", game.Name, pID)
+	}
+
+	game.forfeits[pID] = true
+	left := []string{}
+	for _, p := range game.players {
+		if !game.forfeits[p] {
+			left = append(left, p)
+		}
+	}
+	game.mu.Unlock()
+
+	game.log.Info().Str(logging.PlayerID, pID).Msg("player forfeits")
+	events := []Event{{Type: "forfeit", Player: pID}}
+
+	forfeiter, ok := game.rules.(Forfeiter)
+	if ok {
+		e, err := forfeiter.Forfeit(game.state, pID)
+		if err != nil {
+			game.log.Error().Str(logging.PlayerID, pID).Msgf("forfeit error: %s", err.Error())
+		}
+		events = append(events, e...)
+	}
+
+	switch {
+	case len(left) == 1:
+		events = append(events, game.end(left[0]))
+	case len(left) == 0 || !ok:
+		events = append(events, game.end(""))
+	}
+	game.stateMu.Unlock()
+
+	for _, e := range events {
+		game.emit(e)
+	}
+
+	game.ReleaseHost(pID)
+
+	return nil
+}
+
+// HasForfeited returns true if a player left the running game.
+func (game *Game) HasForfeited(pID string) bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.forfeits[pID]
+}
+
+// end ends the game before its term, and returns the end event.
+func (game *Game) end(winner string) Event {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	game.finished = true
+	game.endTime = time.Now()
+
+	return Event{Type: "end", Data: winner}
+}
+
+// skipped returns true if the turns of a player are skipped, ie the player
+// forfeited and its seat is not controlled by a bot.
+func (game *Game) skipped(pID string) bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.forfeits[pID] && !game.bots[pID]
+}
+
+// forfeit records the forfeit of a Skyjo player. The player is no longer
+// waited for to reveal its initial cards, and its current turn is skipped,
+// unless its seat is controlled by a bot. It returns the events to publish.
+func (s *skyjoState) forfeit(pID string) []Event {
+	events := []Event{}
+	s.record(pID, Move{Kind: Forfeit})
+
+	if !s.game.skipped(pID) {
+		return events
+	}
+
+	if !s.playing {
+		if s.playerAnswerMap[pID] {
+			return events
+		}
+
+		s.playerAnswerMap[pID] = true
+		s.wg.Done()
+		if s.initialized() {
+			events = append(events, Event{Type: "turn", Data: s.startTurnLoop()})
+		}
+
+		return events
+	}
+
+	if s.players[s.current] != pID {
+		return events
+	}
+
+	// the held card is discarded, and the forfeited turn counts as played
+	if s.holding {
+		s.discard = append(s.discard, s.held)
+		s.holding = false
+	}
+	if s.closer != "" {
+		s.lastTurns--
+	}
+
+	return append(events, s.next()...)
+}
@@ -0,0 +1,169 @@
+package games_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestForfeit(t *testing.T) {
+	logger := zerolog.Nop()
+
+	game := newSeededGame(t, 11, "player1", "player2", "player3")
+
+	ends := []string{}
+	game.OnEvent(func(e games.Event) {
+		if e.Type == "end" {
+			ends = append(ends, e.Data)
+		}
+	})
+
+	err := game.Forfeit("player4")
+	if err == nil {
+		t.Errorf("expected error when an unknown player forfeits")
+	}
+
+	// the current player forfeits while holding a card
+	current := game.CurrentPlayer()
+	err = game.Play(current, games.Move{Kind: games.Draw})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = game.Forfeit(current)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if game.CurrentPlayer() == current {
+		t.Errorf("expected turn of player %s to be skipped", current)
+	}
+
+	err = game.Forfeit(current)
+	if err == nil {
+		t.Errorf("expected error when a player forfeits twice")
+	}
+
+	err = game.CheckInvariants()
+	if err != nil {
+		t.Fatalf("unexpected invariant violation: %v", err)
+	}
+
+	for i := 0; i < 60 && !game.IsFinished(); i++ {
+		if game.CurrentPlayer() == "" {
+			// a new round started, the forfeited player is not waited for
+			for _, pID := range game.Players() {
+				moves, _ := games.ParseMoves("Ra1 Rb2")
+				if game.Play(pID, moves...) == nil {
+					_ = game.PlayerInit(pID)
+				}
+			}
+		}
+
+		if game.CurrentPlayer() == current {
+			t.Fatalf("expected turns of player %s to be skipped", current)
+		}
+		playTurn(t, game)
+
+		err = game.CheckInvariants()
+		if err != nil {
+			t.Fatalf("unexpected invariant violation: %v", err)
+		}
+	}
+
+	log := game.Log()
+	parsed, err := games.ParseLog(log.String())
+	if err != nil {
+		t.Fatalf("unexpected error parsing log: %v", err)
+	}
+
+	replayed, err := games.Replay(&logger, parsed)
+	if err != nil {
+		t.Fatalf("unexpected error replaying log: %v", err)
+	}
+
+	if !replayed.HasForfeited(current) {
+		t.Errorf("expected player %s to forfeit in the replayed game", current)
+	}
+
+	// the last player who did not forfeit wins
+	if !game.IsFinished() {
+		var left []string
+		for _, pID := range game.Players() {
+			if pID != current {
+				left = append(left, pID)
+			}
+		}
+
+		err = game.Forfeit(left[0])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !game.IsFinished() {
+			t.Errorf("expected game to end when a single player is left")
+		}
+
+		if len(ends) != 1 || ends[0] != left[1] {
+			t.Errorf("expected player %s to win, got %v", left[1], ends)
+		}
+	}
+
+	err = game.Forfeit(game.Players()[0])
+	if err == nil {
+		t.Errorf("expected error when a player forfeits a finished game")
+	}
+}
+
+func TestForfeitInitialReveals(t *testing.T) {
+	logger := zerolog.Nop()
+
+	game := games.New(&logger, 2, 3)
+	for _, pID := range []string{"player1", "player2", "player3"} {
+		_ = game.AddPlayer(pID)
+	}
+
+	err := game.Forfeit("player1")
+	if err == nil {
+		t.Errorf("expected error when a player forfeits a game not started")
+	}
+
+	err = game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, pID := range []string{"player1", "player2"} {
+		moves, _ := games.ParseMoves("Ra1 Rb2")
+		_ = game.Play(pID, moves...)
+		err = game.PlayerInit(pID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// the turn loop starts when the last player to initialize forfeits
+	err = game.Forfeit("player3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if game.CurrentPlayer() == "" || game.CurrentPlayer() == "player3" {
+		t.Errorf("expected turn loop started without player3, current player %q", game.CurrentPlayer())
+	}
+
+	err = game.Play("player3", games.Move{Kind: games.Draw})
+	if err == nil {
+		t.Errorf("expected error when a forfeited player plays")
+	}
+
+	v, err := game.View("player1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !v.Forfeits["player3"] {
+		t.Errorf("expected forfeit of player3 in view")
+	}
+}
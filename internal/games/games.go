@@ -2,8 +2,8 @@ package games
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"os"
 	"sync"
 	"time"
 
@@ -12,6 +12,7 @@ import (
 	"github.com/goombaio/namegenerator"
 	"github.com/rs/zerolog"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
 )
 
@@ -19,6 +20,11 @@ const (
 	GameTopicPrefix                string = "game-"
 	DefaultClientConnectionTimeout        = 10 * time.Second
 	DefaultWaitForRPCTimeout              = 10 * time.Second
+	InitialReveals                 int    = 2
+	ScoreLimit                     int    = 100
+	// RevealCards is the rpc event asking players to reveal
+	// their initial cards, as many as the game options tell.
+	RevealCards string = "revealCards"
 )
 
 type Game struct {
@@ -34,53 +40,117 @@ type Game struct {
 	Name              string
 	client            *centrifuge.Client
 	sub               *centrifuge.Subscription
-	turn              int
 	waitForRPCTimeout time.Duration
-	playerAnswerMap   map[string]bool
-	wg                sync.WaitGroup
+	mu                sync.Mutex
+	seed              int64
+	finished          bool
+	history           []Record
+	handlers          []EventHandler
+	bots              map[string]bool
+	Options           Options `json:"options"`
+	Type              string  `json:"type"`
+	host              string
+	forfeits          map[string]bool
+	ready             map[string]bool
+	lastActivity      time.Time
+	closed            bool
+	inviteCode        string
+	rules             Ruleset
+	state             State
+	stateMu           sync.Mutex
+}
+
+// Option configures a game.
+type Option func(game *Game)
+
+// WithSeed sets the seed of the game random source. The seed is used to
+// name the game and to shuffle the cards, so that games created with the
+// same seed are dealt the same cards and can be replayed.
+func WithSeed(seed int64) Option {
+	return func(game *Game) {
+		game.seed = seed
+	}
 }
 
 // New creates a new game object with a minimum number of players
 // required to join the game to be able to start it, and a maximum
 // number of players who can join the game.
-func New(l *zerolog.Logger, min, max int) *Game {
+func New(l *zerolog.Logger, min, max int, opts ...Option) *Game {
 	gameID := uuid.New()
-	nameGenerator := namegenerator.NewNameGenerator(time.Now().UTC().UnixNano())
-	output := zerolog.ConsoleWriter{
-		Out:           os.Stderr,
-		TimeFormat:    time.RFC3339,
-		FormatMessage: func(i interface{}) string { return fmt.Sprintf("[game] %s", i) },
-	}
-	log := l.Output(output)
-
-	name := nameGenerator.Generate()
 
 	g := Game{
-		log:               &log,
 		ID:                gameID,
 		MinPlayers:        min,
 		MaxPlayers:        max,
 		players:           []string{},
 		started:           false,
-		TopicName:         GameTopicPrefix + name,
-		Name:              name,
-		turn:              0,
 		waitForRPCTimeout: DefaultWaitForRPCTimeout,
+		seed:              time.Now().UTC().UnixNano(),
+		history:           []Record{},
+		bots:              make(map[string]bool),
+		forfeits:          make(map[string]bool),
+		ready:             make(map[string]bool),
+		lastActivity:      time.Now(),
+		Options:           DefaultOptions(),
+		Type:              Skyjo,
+	}
+
+	for _, opt := range opts {
+		opt(&g)
 	}
 
+	g.rules = ruleset(g.Type)
+
+	g.Name = namegenerator.NewNameGenerator(g.seed).Generate()
+	g.TopicName = GameTopicPrefix + g.Name
+
+	log := logging.Component(l, "game").With().Str(logging.GameID, gameID.String()).Str("game", g.Name).Logger()
+	g.log = &log
+
 	return &g
 }
 
+// Seed returns the seed of the game random source.
+func (game *Game) Seed() int64 {
+	return game.seed
+}
+
+// Close unsubscribes the game from its dedicated topic and disconnects it
+// from the websocket server, eg when the game is removed. The events of a
+// closed game are only handed to its event handlers.
+func (game *Game) Close() {
+	game.mu.Lock()
+	if game.closed {
+		game.mu.Unlock()
+		return
+	}
+	game.closed = true
+	game.mu.Unlock()
+
+	if game.sub != nil {
+		err := game.sub.Unsubscribe()
+		if err != nil {
+			game.log.Error().Msgf("unsubscription error: %s", err.Error())
+		}
+	}
+
+	if game.client != nil {
+		game.client.Close()
+	}
+
+	game.log.Info().Msg("closed")
+}
+
 func (game *Game) messageHandler(e centrifuge.MessageEvent) {
-	game.log.Info().Msgf("[%s] message received from server %s", game.Name, (e.Data))
+	game.log.Info().Msgf("message received from server %s", (e.Data))
 }
 
 func (game *Game) serverPublicationHandler(e centrifuge.ServerPublicationEvent) {
-	game.log.Info().Msgf("[%s] server publication received %s", game.Name, (e.Data))
+	game.log.Info().Msgf("server publication received %s", (e.Data))
 }
 
 func (game *Game) publicationHandler(e centrifuge.PublicationEvent) {
-	game.log.Info().Msgf("[%s] publication received %s", game.Name, (e.Data))
+	game.log.Info().Msgf("publication received %s", (e.Data))
 }
 
 // Connect connects the game to the wrbsocket server.
@@ -133,34 +203,54 @@ func (game *Game) Connect() error {
 
 }
 
-// Start starts the game. If the game is already started, or
-// if the minimum player number registered is not reached, an
-// error is returned.
+// Start starts the game. If the game is already started, if
+// the minimum player number registered is not reached, if players
+// are not ready while the game options require a ready check, or if
+// the game type is unknown, an error is returned.
 func (game *Game) Start() error {
-	var err error
+	game.mu.Lock()
 
 	if game.started {
+		game.mu.Unlock()
 		return fmt.Errorf("[%s] game already started", game.Name)
 	}
 
+	if game.rules == nil {
+		game.mu.Unlock()
+		return fmt.Errorf("[%s] unknown game type %q", game.Name, game.Type)
+	}
+
 	players := game.players
 	if game.MinPlayers != 0 && len(players) < game.MinPlayers {
+		game.mu.Unlock()
 		return fmt.Errorf("[%s] min player number %d not reached yet", game.Name, game.MinPlayers)
 	}
 
+	if game.Options.ReadyCheck && !game.allReady() {
+		game.mu.Unlock()
+		return fmt.Errorf("[%s] players not ready", game.Name)
+	}
+
 	game.started = true
 	game.startTime = time.Now()
+	game.mu.Unlock()
 
-	// publication to all clients who subscribed to a channel
-	_, err = game.publish(`{"type": "rpc", "emitter": "game", "id": "` + game.ID.String() + `", "data": "revealTwoCards"}`)
+	game.stateMu.Lock()
+	state, events, err := game.rules.Setup(game)
 	if err != nil {
-		game.log.Error().Msgf("[%s] publication error: %s", game.Name, err.Error())
+		game.stateMu.Unlock()
+		game.mu.Lock()
+		game.started = false
+		game.mu.Unlock()
+		return fmt.Errorf("[%s] unable to set %s game up: %s", game.Name, game.Type, err.Error())
 	}
+	game.state = state
+	game.stateMu.Unlock()
 
-	// TODO: here, initialize game
-
-	// wait for all players to initialize
-	go game.waitAllPlayersInitialized()
+	// publication to all clients who subscribed to a channel
+	for _, e := range events {
+		game.emit(e)
+	}
 
 	return nil
 }
@@ -181,9 +271,68 @@ func (game *Game) publish(message string) (centrifuge.PublishResult, error) {
 	return res, nil
 }
 
+// Event is a message published on the game dedicated topic.
+type Event struct {
+	Type    string `json:"type"`
+	Emitter string `json:"emitter"`
+	ID      string `json:"id"`
+	Player  string `json:"player,omitempty"`
+	Data    string `json:"data"`
+}
+
+// EventHandler is called for each event published by a game.
+type EventHandler func(e Event)
+
+// OnEvent registers a handler called for each event published by the game,
+// in the publishing goroutine. Handlers must not block.
+func (game *Game) OnEvent(handler EventHandler) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	game.handlers = append(game.handlers, handler)
+}
+
+// publishEvent sends an event message on the game dedicated topic.
+func (game *Game) publishEvent(kind, data string) {
+	game.emit(Event{Type: kind, Data: data})
+}
+
+// emit publishes an event on the game dedicated topic and calls the event
+// handlers. Publication errors are logged. Games which are not connected to
+// the websocket server, eg simulated games, only call the event handlers.
+func (game *Game) emit(e Event) {
+	e.Emitter = "game"
+	e.ID = game.ID.String()
+
+	game.mu.Lock()
+	handlers := game.handlers
+	closed := game.closed
+	game.mu.Unlock()
+
+	if game.sub != nil && !closed {
+		b, err := json.Marshal(e)
+		if err != nil {
+			game.log.Error().Msgf("unable to marshal event %v: %s", e, err.Error())
+			return
+		}
+
+		_, err = game.publish(string(b))
+		if err != nil {
+			game.log.Error().Msgf("publication error: %s", err.Error())
+		}
+	}
+
+	for _, handler := range handlers {
+		handler(e)
+	}
+}
+
 // Stop stops a started game. If the game is not started, an
 // error is returned.
 func (game *Game) Stop() error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
 	if !game.started {
 		return fmt.Errorf("[%s] game not started", game.Name)
 	}
@@ -196,6 +345,9 @@ func (game *Game) Stop() error {
 
 // IsStarted returns true if the game is started.
 func (game *Game) IsStarted() bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
 	return game.started
 }
 
@@ -203,11 +355,14 @@ func (game *Game) IsStarted() bool {
 // the method does nothing. If the maximum number of players is alreary
 // reached, of if the game is already started, the methods returns an error.
 func (game *Game) AddPlayer(id string) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
 	if game.started {
 		return fmt.Errorf("[%s] game alreay started", game.Name)
 	}
 
-	if len(game.players) == game.MaxPlayers {
+	if len(game.players) >= game.MaxPlayers {
 		return fmt.Errorf("[%s] maximum number of players alreay reached", game.Name)
 	}
 
@@ -216,10 +371,42 @@ func (game *Game) AddPlayer(id string) error {
 	}
 
 	game.players = append(game.players, id)
+	game.lastActivity = time.Now()
+	return nil
+}
+
+// SetBotControlled marks the seat of a player as controlled by a bot or by
+// the player, and publishes a seat event.
+func (game *Game) SetBotControlled(pID string, bot bool) error {
+	game.mu.Lock()
+	if !utils.ContainsString(game.players, pID) {
+		game.mu.Unlock()
+		return fmt.Errorf("[%s] unknown player %s", game.Name, pID)
+	}
+	game.bots[pID] = bot
+	game.mu.Unlock()
+
+	data := "player"
+	if bot {
+		data = "bot"
+	}
+	game.emit(Event{Type: "seat", Player: pID, Data: data})
+
 	return nil
 }
 
+// IsBotControlled returns true if the seat of a player is controlled by a bot.
+func (game *Game) IsBotControlled(pID string) bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.bots[pID]
+}
+
 // Players returns game's registered players.
 func (game *Game) Players() []string {
-	return game.players
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return append([]string{}, game.players...)
 }
@@ -0,0 +1,261 @@
+package games
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Hint is a legal move of a player, with an estimate of its value: the
+// expected change of the player's board score. The lower, the better.
+type Hint struct {
+	Moves string  `json:"moves"`
+	Value float64 `json:"value"`
+}
+
+// Hinter is implemented by rulesets suggesting moves to their players.
+type Hinter interface {
+	// Hints returns the legal moves of a player, best first.
+	Hints(s State, pID string) ([]Hint, error)
+}
+
+// HintsEnabled returns true if players may ask for move hints.
+func (game *Game) HintsEnabled() bool {
+	return !game.Settings().NoHints
+}
+
+// Suggest returns the legal moves of a player, best first. Moves are
+// evaluated with the information visible to the player only.
+func (game *Game) Suggest(pID string) ([]Hint, error) {
+	if game.Settings().NoHints {
+		return nil, fmt.Errorf("[%s] hints are disabled", game.Name)
+	}
+
+	hinter, ok := game.rules.(Hinter)
+	if !ok {
+		return nil, fmt.Errorf("[%s] not supported by %s games", game.Name, game.Type)
+	}
+
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	if game.state == nil {
+		return nil, fmt.Errorf("[%s] game not started", game.Name)
+	}
+
+	return hinter.Hints(game.state, pID)
+}
+
+// Suggest returns the legal moves of the player of a view, best first.
+func Suggest(v View) []Hint {
+	e := newEstimator(v)
+	hints := []Hint{}
+
+	switch {
+	case !v.Playing:
+		if e.board.RevealedCount() >= v.Options.InitialReveals {
+			break
+		}
+		for _, sq := range e.squares(false) {
+			hints = append(hints, e.hint(e.reveal(sq), Move{Kind: Reveal, Square: sq}))
+		}
+	case v.Holding:
+		for _, sq := range e.squares(false) {
+			hints = append(hints, e.hint(e.place(sq, v.Held), Move{Kind: Place, Square: sq}))
+			if v.HeldFromDeck {
+				hints = append(hints, e.hint(e.reveal(sq), Move{Kind: Discard, Square: sq}))
+			}
+		}
+		for _, sq := range e.squares(true) {
+			hints = append(hints, e.hint(e.place(sq, v.Held), Move{Kind: Place, Square: sq}))
+		}
+	default:
+		hints = append(hints, e.hint(e.draw(), Move{Kind: Draw}))
+		if v.HasDiscard {
+			for _, sq := range append(e.squares(false), e.squares(true)...) {
+				hints = append(hints, e.hint(e.place(sq, v.Discard), Move{Kind: Take}, Move{Kind: Place, Square: sq}))
+			}
+		}
+	}
+
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Value < hints[j].Value
+	})
+
+	return hints
+}
+
+// estimator evaluates moves on the board of a player, face-down cards being
+// worth the average of the cards the player has not seen.
+type estimator struct {
+	board  Board
+	clears bool
+	unseen []odds
+	mean   float64
+}
+
+// odds is the probability of an unseen card.
+type odds struct {
+	card Card
+	p    float64
+}
+
+func newEstimator(v View) *estimator {
+	e := &estimator{
+		board:  v.Boards[v.Player],
+		clears: v.Options.ColumnClears,
+		unseen: []odds{},
+	}
+
+	counts := make(map[Card]int)
+	for _, card := range v.Options.Cards() {
+		counts[card]++
+	}
+
+	seen := func(card Card) {
+		if counts[card] > 0 {
+			counts[card]--
+		}
+	}
+	for _, board := range v.Boards {
+		for c := 0; c < Columns; c++ {
+			for r := 0; r < Rows; r++ {
+				if board.Slots[c][r].Revealed {
+					seen(board.Slots[c][r].Card)
+				}
+			}
+		}
+	}
+	if v.HasDiscard {
+		seen(v.Discard)
+	}
+	if v.Holding {
+		seen(v.Held)
+	}
+
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	for card := MinCard; card <= MaxCard; card++ {
+		if counts[card] > 0 {
+			p := float64(counts[card]) / float64(total)
+			e.unseen = append(e.unseen, odds{card: card, p: p})
+			e.mean += float64(card) * p
+		}
+	}
+
+	return e
+}
+
+// hint returns the hint of a move with the given value.
+func (e *estimator) hint(value float64, moves ...Move) Hint {
+	return Hint{
+		Moves: FormatMoves(moves),
+		Value: math.Round(value*100) / 100,
+	}
+}
+
+// squares returns the squares of the board still in play, revealed or not.
+func (e *estimator) squares(revealed bool) []Square {
+	result := []Square{}
+	for c := 0; c < Columns; c++ {
+		for r := 0; r < Rows; r++ {
+			slot := e.board.Slots[c][r]
+			if !slot.Cleared && slot.Revealed == revealed {
+				result = append(result, Square{Column: c, Row: r})
+			}
+		}
+	}
+
+	return result
+}
+
+// column returns the estimated score of a column, when the card at a square
+// is revealed to be the given card.
+func (e *estimator) column(sq Square, card Card) float64 {
+	if e.board.Slots[sq.Column][0].Cleared {
+		return 0
+	}
+
+	score := 0.0
+	same := true
+	for r := 0; r < Rows; r++ {
+		slot := e.board.Slots[sq.Column][r]
+		if r == sq.Row {
+			slot.Card, slot.Revealed = card, true
+		}
+		if !slot.Revealed {
+			score += e.mean
+			same = false
+			continue
+		}
+		score += float64(slot.Card)
+		same = same && slot.Card == card
+	}
+
+	// a column of three identical revealed cards is cleared
+	if same && e.clears {
+		return 0
+	}
+
+	return score
+}
+
+// current returns the estimated score of the column of a square.
+func (e *estimator) current(sq Square) float64 {
+	score := 0.0
+	for r := 0; r < Rows; r++ {
+		slot := e.board.Slots[sq.Column][r]
+		if slot.Cleared {
+			return 0
+		}
+		if slot.Revealed {
+			score += float64(slot.Card)
+		} else {
+			score += e.mean
+		}
+	}
+
+	return score
+}
+
+// place returns the expected score change of placing a card on a square.
+func (e *estimator) place(sq Square, card Card) float64 {
+	return e.column(sq, card) - e.current(sq)
+}
+
+// reveal returns the expected score change of revealing a face-down square,
+// which is only due to the chance of clearing its column.
+func (e *estimator) reveal(sq Square) float64 {
+	score := 0.0
+	for _, o := range e.unseen {
+		score += o.p * e.column(sq, o.card)
+	}
+
+	return score - e.current(sq)
+}
+
+// draw returns the expected score change of drawing a card, then placing
+// or discarding it at best.
+func (e *estimator) draw() float64 {
+	hidden := e.squares(false)
+	all := append(e.squares(true), hidden...)
+
+	// the drawn card may be discarded to reveal a face-down card instead
+	discard := math.Inf(1)
+	for _, sq := range hidden {
+		discard = math.Min(discard, e.reveal(sq))
+	}
+
+	score := 0.0
+	for _, o := range e.unseen {
+		best := discard
+		for _, sq := range all {
+			best = math.Min(best, e.place(sq, o.card))
+		}
+		score += o.p * best
+	}
+
+	return score
+}
@@ -0,0 +1,96 @@
+package games_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestSuggest(t *testing.T) {
+	board := games.Board{}
+	for c := 0; c < games.Columns; c++ {
+		for r := 0; r < games.Rows; r++ {
+			board.Slots[c][r] = games.Slot{Card: games.Card(10 + r), Revealed: true}
+		}
+	}
+	board.Slots[0][0] = games.Slot{Card: 5, Revealed: true}
+	board.Slots[0][1] = games.Slot{Card: 5, Revealed: true}
+	board.Slots[0][2] = games.Slot{}
+
+	v := games.View{
+		Player:       "p1",
+		Players:      []string{"p1"},
+		Boards:       map[string]games.Board{"p1": board},
+		Started:      true,
+		Playing:      true,
+		Current:      "p1",
+		Held:         5,
+		Holding:      true,
+		HeldFromDeck: true,
+		Options:      games.DefaultOptions(),
+	}
+
+	hints := games.Suggest(v)
+	if len(hints) != 13 {
+		t.Fatalf("expected 13 hints, got %d: %v", len(hints), hints)
+	}
+
+	if hints[0].Moves != ">a3" {
+		t.Errorf("expected best hint to clear column a, got %v", hints)
+	}
+
+	for i := 1; i < len(hints); i++ {
+		if hints[i].Value < hints[i-1].Value {
+			t.Errorf("expected hints sorted by value, got %v", hints)
+		}
+	}
+}
+
+func TestSuggestGame(t *testing.T) {
+	game := newSeededGame(t, 42, "p1", "p2")
+
+	pID := game.CurrentPlayer()
+	other := "p1"
+	if pID == "p1" {
+		other = "p2"
+	}
+
+	_, err := game.Suggest(other)
+	if err == nil {
+		t.Errorf("expected an error suggesting a move out of turn")
+	}
+
+	// playing the best hints makes a legal turn
+	for game.CurrentPlayer() == pID {
+		hints, err := game.Suggest(pID)
+		if err != nil {
+			t.Fatalf("unexpected error suggesting a move: %v", err)
+		}
+		if len(hints) == 0 {
+			t.Fatalf("expected hints")
+		}
+
+		moves, err := games.ParseMoves(hints[0].Moves)
+		if err != nil {
+			t.Fatalf("unexpected error parsing hint %q: %v", hints[0].Moves, err)
+		}
+
+		err = game.Play(pID, moves...)
+		if err != nil {
+			t.Fatalf("unexpected error playing hint %q: %v", hints[0].Moves, err)
+		}
+	}
+
+	logger := zerolog.Nop()
+	game = games.New(&logger, 2, 2, games.WithoutHints())
+	if game.HintsEnabled() {
+		t.Errorf("expected hints to be disabled")
+	}
+
+	_, err = game.Suggest("p1")
+	if err == nil {
+		t.Errorf("expected an error suggesting a move with hints disabled")
+	}
+}
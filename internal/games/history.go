@@ -0,0 +1,183 @@
+package games
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Record holds the move steps played by a player during a turn.
+// Turn 0 of each round is used for the initial reveals.
+type Record struct {
+	Round  int
+	Turn   int
+	Player string
+	Moves  []Move
+}
+
+// Log is the printable history of a game. Its notation starts with tag
+// pairs, followed by one line per record:
+//
+//	[ID "1c1e0f3a-..."]
+//	[Name "quiet-moon"]
+//	[Players "id1 id2"]
+//	[Seed "42"]
+//
+//	1.0 p1 Ra1=4 Rb2=7
+//	1.1 p2 D7>b2=3 Cb=5
+//
+// where "1.1" is the round and the turn, and "p2" the second player seat.
+// The seed tag is omitted when the seed is not known. An options tag holds
+// the JSON options of games not played with the standard rules.
+type Log struct {
+	ID      string
+	Name    string
+	Players []string
+	Seed    *int64
+	Options *Options
+	Records []Record
+}
+
+// String returns the notation of the game log.
+func (l Log) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "[ID %q]\n", l.ID)
+	fmt.Fprintf(&b, "[Name %q]\n", l.Name)
+	fmt.Fprintf(&b, "[Players %q]\n", strings.Join(l.Players, " "))
+	if l.Seed != nil {
+		fmt.Fprintf(&b, "[Seed %q]\n", strconv.FormatInt(*l.Seed, 10))
+	}
+	if l.Options != nil {
+		o, _ := json.Marshal(l.Options)
+		fmt.Fprintf(&b, "[Options %q]\n", string(o))
+	}
+	b.WriteString("\n")
+
+	for _, r := range l.Records {
+		seat := l.seat(r.Player)
+		fmt.Fprintf(&b, "%d.%d p%d %s\n", r.Round, r.Turn, seat+1, FormatMoves(r.Moves))
+	}
+
+	return b.String()
+}
+
+func (l Log) seat(pID string) int {
+	for i, p := range l.Players {
+		if p == pID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ParseLog parses the notation of a game log. Unknown tags are ignored.
+func ParseLog(s string) (*Log, error) {
+	l := &Log{
+		Players: []string{},
+		Records: []Record{},
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			err := l.parseTag(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", n, err.Error())
+			}
+			continue
+		}
+
+		r, err := l.parseRecord(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err.Error())
+		}
+		l.Records = append(l.Records, r)
+	}
+
+	return l, nil
+}
+
+func (l *Log) parseTag(line string) error {
+	name, value, ok := strings.Cut(strings.Trim(line, "[]"), " ")
+	if !ok {
+		return fmt.Errorf("invalid tag %q", line)
+	}
+
+	value, err := strconv.Unquote(value)
+	if err != nil {
+		return fmt.Errorf("invalid tag value %q: %s", line, err.Error())
+	}
+
+	switch name {
+	case "ID":
+		l.ID = value
+	case "Name":
+		l.Name = value
+	case "Players":
+		l.Players = strings.Fields(value)
+	case "Seed":
+		seed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid seed %q", value)
+		}
+		l.Seed = &seed
+	case "Options":
+		o := DefaultOptions()
+		err = json.Unmarshal([]byte(value), &o)
+		if err != nil {
+			return fmt.Errorf("invalid options %q: %s", value, err.Error())
+		}
+		l.Options = &o
+	}
+
+	return nil
+}
+
+func (l *Log) parseRecord(line string) (Record, error) {
+	var r Record
+
+	fields := strings.SplitN(line, " ", 3)
+	if len(fields) != 3 {
+		return r, fmt.Errorf("invalid record %q", line)
+	}
+
+	round, turn, ok := strings.Cut(fields[0], ".")
+	if !ok {
+		return r, fmt.Errorf("invalid round and turn %q", fields[0])
+	}
+
+	var err error
+	r.Round, err = strconv.Atoi(round)
+	if err != nil {
+		return r, fmt.Errorf("invalid round %q", round)
+	}
+
+	r.Turn, err = strconv.Atoi(turn)
+	if err != nil {
+		return r, fmt.Errorf("invalid turn %q", turn)
+	}
+
+	seat, err := strconv.Atoi(strings.TrimPrefix(fields[1], "p"))
+	if err != nil || seat < 1 || seat > len(l.Players) {
+		return r, fmt.Errorf("invalid player seat %q", fields[1])
+	}
+	r.Player = l.Players[seat-1]
+
+	r.Moves, err = ParseMoves(fields[2])
+	if err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,90 @@
+package games
+
+import (
+	"fmt"
+)
+
+// CheckInvariants checks the consistency of the game state, and returns an
+// error describing the first violated rule. It is used to fuzz the rules
+// engine with simulated games.
+func (game *Game) CheckInvariants() error {
+	if !game.IsStarted() {
+		return nil
+	}
+
+	return game.skyjo(func(s *skyjoState) error {
+		return s.checkInvariants()
+	})
+}
+
+// checkInvariants checks the consistency of the state of a Skyjo game.
+func (s *skyjoState) checkInvariants() error {
+	game := s.game
+
+	// every card of the deck is either in the draw pile, in the discard pile,
+	// on a board, or held by the current player.
+	counts := make(map[Card]int)
+	for _, card := range s.options.Cards() {
+		counts[card]++
+	}
+	for _, card := range s.deck {
+		counts[card]--
+	}
+	for _, card := range s.discard {
+		counts[card]--
+	}
+	if s.holding {
+		counts[s.held]--
+	}
+
+	for _, pID := range s.players {
+		board, ok := s.boards[pID]
+		if !ok {
+			return fmt.Errorf("[%s] player %s has no board", game.Name, pID)
+		}
+
+		for c := 0; c < Columns; c++ {
+			cleared := board.Slots[c][0].Cleared
+			for r := 0; r < Rows; r++ {
+				slot := board.Slots[c][r]
+				if slot.Cleared != cleared {
+					return fmt.Errorf("[%s] column %c of player %s is partially cleared", game.Name, 'a'+c, pID)
+				}
+				if !slot.Cleared {
+					counts[slot.Card]--
+				}
+			}
+
+			if s.options.ColumnClears && !cleared && !s.holding && s.playing && board.Slots[c][0].Revealed {
+				same := true
+				for r := 1; r < Rows; r++ {
+					slot := board.Slots[c][r]
+					same = same && slot.Revealed && slot.Card == board.Slots[c][0].Card
+				}
+				if same {
+					return fmt.Errorf("[%s] column %c of player %s should be cleared", game.Name, 'a'+c, pID)
+				}
+			}
+		}
+	}
+
+	for card, n := range counts {
+		if n != 0 {
+			return fmt.Errorf("[%s] card %d count is off by %d", game.Name, card, -n)
+		}
+	}
+
+	if len(s.discard) == 0 && !s.holding {
+		return fmt.Errorf("[%s] empty discard pile", game.Name)
+	}
+
+	if s.playing && (s.current < 0 || s.current >= len(s.players)) {
+		return fmt.Errorf("[%s] invalid current player index %d", game.Name, s.current)
+	}
+
+	if s.finished && s.playing {
+		return fmt.Errorf("[%s] finished game still playing", game.Name)
+	}
+
+	return nil
+}
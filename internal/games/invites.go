@@ -0,0 +1,80 @@
+package games
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// InviteCodeLength is the number of characters of invite codes.
+	InviteCodeLength int = 6
+	// inviteCodeAlphabet leaves out characters easily mistaken for others, eg O and 0.
+	inviteCodeAlphabet string = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// NewInviteCode returns a random invite code, short enough to be shared by hand.
+func NewInviteCode() (string, error) {
+	var b strings.Builder
+
+	max := big.NewInt(int64(len(inviteCodeAlphabet)))
+	for i := 0; i < InviteCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("unable to generate invite code: %s", err.Error())
+		}
+		b.WriteByte(inviteCodeAlphabet[n.Int64()])
+	}
+
+	return b.String(), nil
+}
+
+// NormalizeInviteCode returns an invite code as generated, whatever the
+// case and spaces typed by a player.
+func NormalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+}
+
+// WithInviteCode makes the game private: it is hidden from public listings,
+// and only joined with its invite code.
+func WithInviteCode(code string) Option {
+	return func(game *Game) {
+		game.inviteCode = code
+	}
+}
+
+// IsPrivate returns true if the game is only joined with its invite code.
+func (game *Game) IsPrivate() bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.inviteCode != ""
+}
+
+// InviteCode returns the invite code of a private game, or an empty string.
+func (game *Game) InviteCode() string {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.inviteCode
+}
+
+// SetInviteCode replaces the invite code of a private game, eg when it was
+// shared with unwanted players. The previous code no longer joins the game.
+func (game *Game) SetInviteCode(code string) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if game.inviteCode == "" {
+		return fmt.Errorf("[%s] game is not private", game.Name)
+	}
+
+	if code == "" {
+		return fmt.Errorf("[%s] empty invite code", game.Name)
+	}
+
+	game.inviteCode = code
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package games_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestInviteCode(t *testing.T) {
+	logger := zerolog.Nop()
+
+	code, err := games.NewInviteCode()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(code) != games.InviteCodeLength || strings.ToUpper(code) != code {
+		t.Errorf("expected %d upper case characters, got %q", games.InviteCodeLength, code)
+	}
+
+	if games.NormalizeInviteCode(" "+strings.ToLower(code[:3])+" "+code[3:]) != code {
+		t.Errorf("expected typed code to be normalized to %s", code)
+	}
+
+	public := games.New(&logger, 2, 2)
+	if public.IsPrivate() {
+		t.Errorf("expected game to be public")
+	}
+	err = public.SetInviteCode(code)
+	if err == nil {
+		t.Errorf("expected error when setting the invite code of a public game")
+	}
+
+	private := games.New(&logger, 2, 2, games.WithInviteCode(code))
+	if !private.IsPrivate() || private.InviteCode() != code {
+		t.Errorf("expected private game with invite code %s", code)
+	}
+
+	err = private.SetInviteCode("ABCDEF")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	b, err := json.Marshal(private)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	var metadata struct {
+		Private    bool   `json:"private"`
+		InviteCode string `json:"inviteCode"`
+	}
+	err = json.Unmarshal(b, &metadata)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !metadata.Private || metadata.InviteCode != "ABCDEF" {
+		t.Errorf("expected private game metadata with rotated invite code, got %s", string(b))
+	}
+}
@@ -0,0 +1,226 @@
+package games
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// WithHost sets the player hosting the game, who manages its lobby.
+func WithHost(pID string) Option {
+	return func(game *Game) {
+		game.host = pID
+	}
+}
+
+// Host returns the ID of the player hosting the game, or an empty
+// string if the game has no host.
+func (game *Game) Host() string {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.host
+}
+
+// ReleaseHost hands the hosting of the game over to the first other player
+// of the game when the given player hosts it, eg when the host leaves, and
+// publishes a host event. The game has no host left if no other player joined it.
+func (game *Game) ReleaseHost(pID string) {
+	game.mu.Lock()
+	if pID == "" || game.host != pID {
+		game.mu.Unlock()
+		return
+	}
+
+	game.host = ""
+	for _, p := range game.players {
+		if p != pID {
+			game.host = p
+			break
+		}
+	}
+	host := game.host
+	game.mu.Unlock()
+
+	game.log.Info().Str(logging.PlayerID, pID).Msgf("host handed over to %q", host)
+	game.emit(Event{Type: "host", Data: host})
+}
+
+// RemovePlayer removes a player from the lobby of a game not started yet.
+// The hosting of the game is handed over if the player hosts it.
+func (game *Game) RemovePlayer(pID string) error {
+	game.mu.Lock()
+	if game.started {
+		game.mu.Unlock()
+		return fmt.Errorf("[%s] game already started", game.Name)
+	}
+
+	if !utils.ContainsString(game.players, pID) {
+		game.mu.Unlock()
+		return fmt.Errorf("[%s] unknown player %s", game.Name, pID)
+	}
+
+	players := []string{}
+	for _, p := range game.players {
+		if p != pID {
+			players = append(players, p)
+		}
+	}
+	game.players = players
+	delete(game.ready, pID)
+	game.lastActivity = time.Now()
+	game.mu.Unlock()
+
+	game.ReleaseHost(pID)
+
+	return nil
+}
+
+// SetReady marks a player of the lobby of a game not started yet as ready
+// to play or not, and publishes a ready event.
+func (game *Game) SetReady(pID string, ready bool) error {
+	game.mu.Lock()
+	if game.started {
+		game.mu.Unlock()
+		return fmt.Errorf("[%s] game already started", game.Name)
+	}
+
+	if !utils.ContainsString(game.players, pID) {
+		game.mu.Unlock()
+		return fmt.Errorf("[%s] unknown player %s", game.Name, pID)
+	}
+
+	if ready {
+		game.ready[pID] = true
+	} else {
+		delete(game.ready, pID)
+	}
+	game.lastActivity = time.Now()
+	game.mu.Unlock()
+
+	game.emit(Event{Type: "ready", Player: pID, Data: strconv.FormatBool(ready)})
+
+	return nil
+}
+
+// IsReady returns true if a player of the lobby is ready to play.
+func (game *Game) IsReady(pID string) bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.ready[pID]
+}
+
+// AllReady returns true when the minimum number of players joined the
+// lobby, and all of them are ready to play.
+func (game *Game) AllReady() bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.allReady()
+}
+
+// allReady returns true when the minimum number of players joined the lobby,
+// and all of them are ready. It must be called with the game lock held.
+func (game *Game) allReady() bool {
+	if len(game.players) == 0 || len(game.players) < game.MinPlayers {
+		return false
+	}
+
+	for _, pID := range game.players {
+		if !game.ready[pID] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetOptions changes the options of a game not started yet.
+func (game *Game) SetOptions(options Options) error {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	if game.started {
+		return fmt.Errorf("[%s] game already started", game.Name)
+	}
+
+	err := options.Validate(game.MinPlayers, game.MaxPlayers)
+	if err != nil {
+		return fmt.Errorf("[%s] invalid options: %s", game.Name, err.Error())
+	}
+
+	game.Options = options
+	game.lastActivity = time.Now()
+
+	return nil
+}
+
+// Settings returns the options of the game. Options are read through it
+// while the game may still be in its lobby, as they change until it starts.
+func (game *Game) Settings() Options {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.Options
+}
+
+// LastActivity returns the time of the last change of the lobby of the
+// game: its creation, a player joining or leaving, getting ready or not,
+// or its options changing.
+func (game *Game) LastActivity() time.Time {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.lastActivity
+}
+
+// MarshalJSON returns the JSON metadata of the game, including its host,
+// the players ready to play, and its invite code if the game is private.
+func (game *Game) MarshalJSON() ([]byte, error) {
+	return game.marshal(true)
+}
+
+// PublicJSON returns the JSON metadata of the game listed to any client,
+// with the IDs of its host and of the players ready to play blanked. The
+// IDs of the players are only carried by the events of the game.
+func (game *Game) PublicJSON() ([]byte, error) {
+	return game.marshal(false)
+}
+
+// marshal returns the JSON metadata of the game, with the IDs of its
+// players or not.
+func (game *Game) marshal(ids bool) ([]byte, error) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	host := ""
+	ready := []string{}
+	if ids {
+		host = game.host
+		for _, pID := range game.players {
+			if game.ready[pID] {
+				ready = append(ready, pID)
+			}
+		}
+	}
+
+	type metadata Game
+	return json.Marshal(struct {
+		*metadata
+		Host       string   `json:"host"`
+		Ready      []string `json:"ready"`
+		Private    bool     `json:"private"`
+		InviteCode string   `json:"inviteCode,omitempty"`
+	}{
+		metadata:   (*metadata)(game),
+		Host:       host,
+		Ready:      ready,
+		Private:    game.inviteCode != "",
+		InviteCode: game.inviteCode,
+	})
+}
@@ -0,0 +1,210 @@
+package games_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestLobby(t *testing.T) {
+	logger := zerolog.Nop()
+	game := games.New(&logger, 2, 3, games.WithHost("p1"))
+
+	for _, pID := range []string{"p1", "p2", "p3"} {
+		err := game.AddPlayer(pID)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+
+	hosts := []string{}
+	game.OnEvent(func(e games.Event) {
+		if e.Type == "host" {
+			hosts = append(hosts, e.Data)
+		}
+	})
+
+	b, err := json.Marshal(game)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	var metadata struct {
+		ID   string `json:"id"`
+		Host string `json:"host"`
+	}
+	err = json.Unmarshal(b, &metadata)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if metadata.Host != "p1" || metadata.ID != game.ID.String() {
+		t.Errorf("expected metadata of game %s hosted by p1, got %s", game.ID.String(), string(b))
+	}
+
+	err = game.RemovePlayer("p4")
+	if err == nil {
+		t.Errorf("expected error while removing an unknown player")
+	}
+
+	err = game.RemovePlayer("p2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if game.Host() != "p1" || len(hosts) != 0 {
+		t.Errorf("expected host p1 unchanged, got %s", game.Host())
+	}
+
+	err = game.RemovePlayer("p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if game.Host() != "p3" {
+		t.Errorf("expected host handed over to p3, got %q", game.Host())
+	}
+	if len(game.Players()) != 1 {
+		t.Errorf("expected 1 player left, got %v", game.Players())
+	}
+
+	game.ReleaseHost("p3")
+	if game.Host() != "" {
+		t.Errorf("expected no host left, got %q", game.Host())
+	}
+	if len(hosts) != 2 || hosts[0] != "p3" || hosts[1] != "" {
+		t.Errorf("expected host events p3 then none, got %v", hosts)
+	}
+
+	options := games.DefaultOptions()
+	options.ScoreLimit = 0
+	err = game.SetOptions(options)
+	if err == nil {
+		t.Errorf("expected error while setting invalid options")
+	}
+
+	options.ScoreLimit = 50
+	err = game.SetOptions(options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if game.Options.ScoreLimit != 50 {
+		t.Errorf("expected score limit 50, got %d", game.Options.ScoreLimit)
+	}
+
+	_ = game.AddPlayer("p1")
+	err = game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	err = game.SetOptions(games.DefaultOptions())
+	if err == nil {
+		t.Errorf("expected error while setting options of a started game")
+	}
+
+	err = game.RemovePlayer("p1")
+	if err == nil {
+		t.Errorf("expected error while removing a player from a started game")
+	}
+}
+
+func TestReady(t *testing.T) {
+	logger := zerolog.Nop()
+	game := games.New(&logger, 2, 3, games.WithOptions(games.Options{
+		ScoreLimit: games.ScoreLimit, InitialReveals: games.InitialReveals, ReadyCheck: true,
+	}))
+
+	ready := []string{}
+	game.OnEvent(func(e games.Event) {
+		if e.Type == "ready" {
+			ready = append(ready, e.Player+"="+e.Data)
+		}
+	})
+
+	err := game.SetReady("p1", true)
+	if err == nil {
+		t.Errorf("expected error while an unknown player gets ready")
+	}
+
+	for _, pID := range []string{"p1", "p2"} {
+		err = game.AddPlayer(pID)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+
+	err = game.SetReady("p1", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !game.IsReady("p1") || game.AllReady() {
+		t.Errorf("expected p1 only to be ready")
+	}
+
+	err = game.Start()
+	if err == nil {
+		t.Errorf("expected error while starting a game whose players are not ready")
+	}
+
+	err = game.SetReady("p2", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !game.AllReady() {
+		t.Errorf("expected all players to be ready")
+	}
+
+	// a new player is not ready
+	err = game.AddPlayer("p3")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if game.AllReady() {
+		t.Errorf("expected new player p3 not to be ready")
+	}
+
+	err = game.RemovePlayer("p3")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	b, err := json.Marshal(game)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	var metadata struct {
+		Ready []string `json:"ready"`
+	}
+	err = json.Unmarshal(b, &metadata)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(metadata.Ready) != 2 {
+		t.Errorf("expected 2 ready players listed, got %s", string(b))
+	}
+
+	// getting ready or not is a lobby activity
+	before := game.LastActivity()
+	time.Sleep(time.Millisecond)
+	err = game.SetReady("p2", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !game.LastActivity().After(before) {
+		t.Errorf("expected lobby activity to be recorded")
+	}
+	if game.IsReady("p2") || game.AllReady() {
+		t.Errorf("expected p2 not to be ready anymore")
+	}
+
+	expected := []string{"p1=true", "p2=true", "p2=false"}
+	if len(ready) != len(expected) {
+		t.Fatalf("expected ready events %v, got %v", expected, ready)
+	}
+	for i := range expected {
+		if ready[i] != expected[i] {
+			t.Errorf("expected ready events %v, got %v", expected, ready)
+		}
+	}
+}
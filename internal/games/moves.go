@@ -0,0 +1,78 @@
+package games
+
+// MoveKind defines the kind of a move step.
+type MoveKind int
+
+const (
+	Reveal  MoveKind = iota // Reveal turns a face-down card over, eg "Ra1".
+	Draw                    // Draw takes the top card of the draw pile, eg "D7".
+	Take                    // Take takes the top card of the discard pile, eg "T5".
+	Place                   // Place swaps the held card with a board card, eg ">b2".
+	Discard                 // Discard discards the held card and reveals a board card, eg "/c3".
+	Clear                   // Clear removes a column of identical cards, eg "Cb=5".
+	Forfeit                 // Forfeit records that the player left the game, eg "F".
+)
+
+// Move is a single step of a player's turn. A turn is made of one or several
+// steps: a draw from the draw pile is followed by a placement or a discard,
+// a take from the discard pile is followed by a placement.
+//
+// Card holds the drawn or taken card for Draw and Take moves, the card
+// uncovered at the square for Reveal, Place and Discard moves, and the
+// cleared card for Clear moves. Known is false when the card is not
+// provided, which is the case of moves sent by players.
+type Move struct {
+	Kind   MoveKind
+	Square Square
+	Card   Card
+	Known  bool
+}
+
+// Legal returns the legal moves of the player of a view, in the order of
+// their notation: reveals before the first turn, then draws and takes, or
+// placements and discards of the held card, square by square.
+func Legal(v View) [][]Move {
+	board := v.Boards[v.Player]
+	moves := [][]Move{}
+
+	squares := func(hidden bool) []Square {
+		result := []Square{}
+		for c := 0; c < Columns; c++ {
+			for r := 0; r < Rows; r++ {
+				slot := board.Slots[c][r]
+				if !slot.Cleared && (!hidden || !slot.Revealed) {
+					result = append(result, Square{Column: c, Row: r})
+				}
+			}
+		}
+		return result
+	}
+
+	switch {
+	case !v.Playing:
+		if board.RevealedCount() >= v.Options.InitialReveals {
+			break
+		}
+		for _, sq := range squares(true) {
+			moves = append(moves, []Move{{Kind: Reveal, Square: sq}})
+		}
+	case v.Holding:
+		for _, sq := range squares(false) {
+			moves = append(moves, []Move{{Kind: Place, Square: sq}})
+		}
+		if v.HeldFromDeck {
+			for _, sq := range squares(true) {
+				moves = append(moves, []Move{{Kind: Discard, Square: sq}})
+			}
+		}
+	default:
+		moves = append(moves, []Move{{Kind: Draw}})
+		if v.HasDiscard {
+			for _, sq := range squares(false) {
+				moves = append(moves, []Move{{Kind: Take}, {Kind: Place, Square: sq}})
+			}
+		}
+	}
+
+	return moves
+}
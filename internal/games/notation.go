@@ -0,0 +1,237 @@
+package games
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Skyjo moves notation
+//
+// Each move step is written with a single letter or symbol, followed by its
+// square and, optionally, the card involved:
+//
+//	Ra1=4   reveal square a1, which holds a 4
+//	D7      draw a 7 from the draw pile
+//	T-2     take the -2 on top of the discard pile
+//	>b2=3   place the held card on b2, the replaced 3 goes to the discard pile
+//	/c3=8   discard the held card and reveal c3, which holds an 8
+//	Cb=5    column b is cleared, it was made of three 5
+//	F       the player forfeits the game
+//
+// Columns are noted from a to d and rows from 1 to 3. Cards are omitted when
+// unknown, eg "D>b2" draws a card and places it on b2 whatever its value.
+// Steps of a turn may be separated by spaces; a draw or take step is printed
+// stuck to the following step, eg "D7>b2=3 Cb=5".
+
+// String returns the notation of a move step.
+func (m Move) String() string {
+	var b strings.Builder
+
+	switch m.Kind {
+	case Reveal:
+		b.WriteString("R" + m.Square.String())
+	case Draw:
+		b.WriteString("D")
+	case Take:
+		b.WriteString("T")
+	case Place:
+		b.WriteString(">" + m.Square.String())
+	case Discard:
+		b.WriteString("/" + m.Square.String())
+	case Clear:
+		b.WriteString("C" + string(rune('a'+m.Square.Column)))
+	case Forfeit:
+		return "F"
+	default:
+		return fmt.Sprintf("?%d", m.Kind)
+	}
+
+	if m.Known {
+		if m.Kind != Draw && m.Kind != Take {
+			b.WriteString("=")
+		}
+		b.WriteString(strconv.Itoa(int(m.Card)))
+	}
+
+	return b.String()
+}
+
+// FormatMoves returns the notation of a sequence of move steps.
+func FormatMoves(moves []Move) string {
+	var b strings.Builder
+
+	for i, m := range moves {
+		if i > 0 && moves[i-1].Kind != Draw && moves[i-1].Kind != Take {
+			b.WriteString(" ")
+		}
+		b.WriteString(m.String())
+	}
+
+	return b.String()
+}
+
+// ParseSquare parses a square notation, eg "b2".
+func ParseSquare(s string) (Square, error) {
+	if len(s) != 2 {
+		return Square{}, fmt.Errorf("invalid square %q", s)
+	}
+
+	sq := Square{
+		Column: int(s[0] - 'a'),
+		Row:    int(s[1] - '1'),
+	}
+	if !sq.Valid() {
+		return Square{}, fmt.Errorf("invalid square %q", s)
+	}
+
+	return sq, nil
+}
+
+// ParseMove parses the notation of a single move step, eg "D7" or ">b2".
+func ParseMove(s string) (Move, error) {
+	moves, err := ParseMoves(s)
+	if err != nil {
+		return Move{}, err
+	}
+
+	if len(moves) != 1 {
+		return Move{}, fmt.Errorf("expected a single move in %q, got %d", s, len(moves))
+	}
+
+	return moves[0], nil
+}
+
+// ParseMoves parses the notation of a sequence of move steps, eg "D7>b2=3 Cb=5".
+func ParseMoves(s string) ([]Move, error) {
+	p := &parser{input: s}
+	moves := []Move{}
+
+	for {
+		p.skipSpaces()
+		if p.done() {
+			break
+		}
+
+		m, err := p.move()
+		if err != nil {
+			return nil, fmt.Errorf("invalid notation %q: %s", s, err.Error())
+		}
+		moves = append(moves, m)
+	}
+
+	if len(moves) == 0 {
+		return nil, fmt.Errorf("invalid notation %q: no move", s)
+	}
+
+	return moves, nil
+}
+
+// parser reads move steps from a notation string.
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) move() (Move, error) {
+	var m Move
+	var err error
+
+	c := p.peek()
+	p.pos++
+
+	switch c {
+	case 'R':
+		m.Kind = Reveal
+	case 'D':
+		m.Kind = Draw
+	case 'T':
+		m.Kind = Take
+	case '>':
+		m.Kind = Place
+	case '/':
+		m.Kind = Discard
+	case 'C':
+		m.Kind = Clear
+	case 'F':
+		m.Kind = Forfeit
+		return m, nil
+	default:
+		return Move{}, fmt.Errorf("unexpected character %q at position %d", c, p.pos-1)
+	}
+
+	switch m.Kind {
+	case Draw, Take:
+		if p.peek() == '-' || isDigit(p.peek()) {
+			m.Card, err = p.card()
+			m.Known = true
+		}
+		return m, err
+	case Clear:
+		column := int(p.peek() - 'a')
+		if p.done() || column < 0 || column >= Columns {
+			return Move{}, fmt.Errorf("invalid column at position %d", p.pos)
+		}
+		p.pos++
+		m.Square = Square{Column: column}
+	default:
+		if p.pos+2 > len(p.input) {
+			return Move{}, fmt.Errorf("missing square at position %d", p.pos)
+		}
+		m.Square, err = ParseSquare(p.input[p.pos : p.pos+2])
+		if err != nil {
+			return Move{}, err
+		}
+		p.pos += 2
+	}
+
+	if p.peek() == '=' {
+		p.pos++
+		m.Card, err = p.card()
+		m.Known = true
+	}
+
+	return m, err
+}
+
+func (p *parser) card() (Card, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for isDigit(p.peek()) {
+		p.pos++
+	}
+
+	v, err := strconv.Atoi(p.input[start:p.pos])
+	if err != nil {
+		return 0, fmt.Errorf("invalid card at position %d", start)
+	}
+
+	if Card(v) < MinCard || Card(v) > MaxCard {
+		return 0, fmt.Errorf("card %d out of range at position %d", v, start)
+	}
+
+	return Card(v), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,140 @@
+package games_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestParseMoves(t *testing.T) {
+	tests := []struct {
+		notation string
+		expected []games.Move
+	}{
+		{"Ra1", []games.Move{{Kind: games.Reveal, Square: games.Square{Column: 0, Row: 0}}}},
+		{"Rd3=-2", []games.Move{{Kind: games.Reveal, Square: games.Square{Column: 3, Row: 2}, Card: -2, Known: true}}},
+		{"D>b2", []games.Move{{Kind: games.Draw}, {Kind: games.Place, Square: games.Square{Column: 1, Row: 1}}}},
+		{"D7/c3=8", []games.Move{{Kind: games.Draw, Card: 7, Known: true}, {Kind: games.Discard, Square: games.Square{Column: 2, Row: 2}, Card: 8, Known: true}}},
+		{"T12>a1=0 Cb=5", []games.Move{
+			{Kind: games.Take, Card: 12, Known: true},
+			{Kind: games.Place, Square: games.Square{Column: 0, Row: 0}, Card: 0, Known: true},
+			{Kind: games.Clear, Square: games.Square{Column: 1}, Card: 5, Known: true},
+		}},
+		{" Ra1  Rb1 ", []games.Move{{Kind: games.Reveal}, {Kind: games.Reveal, Square: games.Square{Column: 1}}}},
+		{"D7F", []games.Move{{Kind: games.Draw, Card: 7, Known: true}, {Kind: games.Forfeit}}},
+	}
+
+	for _, tt := range tests {
+		moves, err := games.ParseMoves(tt.notation)
+		if err != nil {
+			t.Errorf("unexpected error parsing %q: %s", tt.notation, err.Error())
+			continue
+		}
+
+		if len(moves) != len(tt.expected) {
+			t.Errorf("expected %d moves parsing %q, got %d", len(tt.expected), tt.notation, len(moves))
+			continue
+		}
+
+		for i := range moves {
+			if moves[i] != tt.expected[i] {
+				t.Errorf("expected move %#v parsing %q, got %#v", tt.expected[i], tt.notation, moves[i])
+			}
+		}
+	}
+}
+
+func TestParseMovesErrors(t *testing.T) {
+	for _, notation := range []string{"", "  ", "X", "Re1", "Ra4", "R", "Ra", "D13", "D-3", ">b2=", "Ce", "C"} {
+		_, err := games.ParseMoves(notation)
+		if err == nil {
+			t.Errorf("expected error parsing %q", notation)
+		}
+	}
+
+	_, err := games.ParseMove("D>b2")
+	if err == nil {
+		t.Errorf("expected error parsing several moves as a single move")
+	}
+}
+
+func TestFormatMoves(t *testing.T) {
+	for _, notation := range []string{"Ra1=4 Rb2=7", "D7>b2=3 Cb=5", "D-2/c3=8", "T5>a1", "D>d3"} {
+		moves, err := games.ParseMoves(notation)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %s", notation, err.Error())
+		}
+
+		if games.FormatMoves(moves) != notation {
+			t.Errorf("expected %q, got %q", notation, games.FormatMoves(moves))
+		}
+	}
+}
+
+func TestLog(t *testing.T) {
+	var err error
+
+	logger := zerolog.Nop()
+
+	game := games.New(&logger, 2, 2)
+
+	for _, pID := range []string{"player1", "player2"} {
+		err = game.AddPlayer(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when adding a player: %v", err)
+		}
+	}
+
+	err = game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error when starting the game: %v", err)
+	}
+
+	err = game.PlayerInit("player1")
+	if err == nil {
+		t.Errorf("expected error when initializing a player who did not reveal its cards")
+	}
+
+	moves, _ := games.ParseMoves("Ra1 Rb2")
+	err = game.Play("player1", moves...)
+	if err != nil {
+		t.Errorf("unexpected error when revealing cards: %v", err)
+	}
+
+	move, _ := games.ParseMove("Rc3")
+	err = game.Play("player1", move)
+	if err == nil {
+		t.Errorf("expected error when revealing too many cards")
+	}
+
+	move, _ = games.ParseMove("D")
+	err = game.Play("player2", move)
+	if err == nil {
+		t.Errorf("expected error when drawing a card before the first turn")
+	}
+
+	err = game.PlayerInit("player1")
+	if err != nil {
+		t.Errorf("unexpected error when initializing player1: %v", err)
+	}
+
+	log := game.Log()
+	if len(log.Records) != 1 || len(log.Records[0].Moves) != 2 {
+		t.Fatalf("expected a single record of 2 moves, got %#v", log.Records)
+	}
+
+	parsed, err := games.ParseLog(log.String())
+	if err != nil {
+		t.Fatalf("unexpected error parsing log %q: %s", log.String(), err.Error())
+	}
+
+	if parsed.String() != log.String() {
+		t.Errorf("expected parsed log %q, got %q", log.String(), parsed.String())
+	}
+
+	if parsed.Records[0].Player != "player1" || parsed.Records[0].Round != 1 || parsed.Records[0].Turn != 0 {
+		t.Errorf("unexpected parsed record %#v", parsed.Records[0])
+	}
+}
@@ -0,0 +1,139 @@
+package games
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Options are the variants and house rules of a game.
+type Options struct {
+	// TurnTimeout is the number of seconds a player has to play a turn,
+	// after which the turn is played automatically. No timeout if 0.
+	TurnTimeout int `json:"turnTimeout"`
+	// ScoreLimit ends the game when a player total score reaches it.
+	ScoreLimit int `json:"scoreLimit"`
+	// InitialReveals is the number of cards revealed by each player
+	// at the beginning of a round.
+	InitialReveals int `json:"initialReveals"`
+	// ColumnClears removes the columns of three identical revealed cards.
+	ColumnClears bool `json:"columnClears"`
+	// DoublePenalty doubles the round score of the player who closed the
+	// round when it is positive and not strictly the lowest one.
+	DoublePenalty bool `json:"doublePenalty"`
+	// Deck is the number of copies of each card of the deck.
+	// The standard deck is used if empty.
+	Deck map[Card]int `json:"deck,omitempty"`
+	// NoHints disables the move hints, eg in ranked games.
+	NoHints bool `json:"noHints"`
+	// ReadyCheck prevents the game from starting until all the players
+	// of the lobby are ready.
+	ReadyCheck bool `json:"readyCheck"`
+	// AutoStart starts the game after a countdown once all the players
+	// of the lobby are ready and the minimum number of players is reached.
+	AutoStart bool `json:"autoStart"`
+	// StartWhenFull starts the game as soon as its last seat is taken.
+	StartWhenFull bool `json:"startWhenFull"`
+	// LobbyTimeout is the number of seconds without activity after which
+	// the lobby of a game not started yet is closed. The server default
+	// applies if 0.
+	LobbyTimeout int `json:"lobbyTimeout"`
+}
+
+// DefaultOptions returns the standard Skyjo rules.
+func DefaultOptions() Options {
+	return Options{
+		ScoreLimit:     ScoreLimit,
+		InitialReveals: InitialReveals,
+		ColumnClears:   true,
+		DoublePenalty:  true,
+	}
+}
+
+// WithOptions sets the variants and house rules of the game.
+func WithOptions(options Options) Option {
+	return func(game *Game) {
+		game.Options = options
+	}
+}
+
+// WithoutHints disables move hints, eg in ranked games.
+func WithoutHints() Option {
+	return func(game *Game) {
+		game.Options.NoHints = true
+	}
+}
+
+// MinPlayers is the minimum number of players of a game.
+const MinPlayers int = 2
+
+// Validate checks the options of a game with min to max players. The deck,
+// the standard one or a custom one, must deal a board to every player.
+func (o Options) Validate(min, max int) error {
+	if min < MinPlayers {
+		return fmt.Errorf("min players %d must be at least %d", min, MinPlayers)
+	}
+
+	if max < min {
+		return fmt.Errorf("max players %d must be at least min players %d", max, min)
+	}
+
+	if o.TurnTimeout < 0 {
+		return fmt.Errorf("negative turn timeout %d", o.TurnTimeout)
+	}
+
+	if o.LobbyTimeout < 0 {
+		return fmt.Errorf("negative lobby timeout %d", o.LobbyTimeout)
+	}
+
+	if o.ScoreLimit <= 0 {
+		return fmt.Errorf("score limit %d must be positive", o.ScoreLimit)
+	}
+
+	if o.InitialReveals < 0 || o.InitialReveals > Rows*Columns {
+		return fmt.Errorf("initial reveals %d must be between 0 and %d", o.InitialReveals, Rows*Columns)
+	}
+
+	total := 0
+	for card, n := range o.Deck {
+		if card < MinCard || card > MaxCard {
+			return fmt.Errorf("deck card %d must be between %d and %d", card, MinCard, MaxCard)
+		}
+		if n < 0 {
+			return fmt.Errorf("negative number of card %d in deck", card)
+		}
+		total += n
+	}
+	if len(o.Deck) == 0 {
+		total = len(StandardDeck())
+	}
+
+	// every player is dealt a board, a card is turned over to start the
+	// discard pile, and at least one card must be left to draw.
+	needed := max*Rows*Columns + 2
+	if total < needed {
+		return fmt.Errorf("deck of %d cards too small for %d players, %d cards needed", total, max, needed)
+	}
+
+	return nil
+}
+
+// IsDefault returns true if the options are the standard rules.
+func (o Options) IsDefault() bool {
+	return reflect.DeepEqual(o, DefaultOptions())
+}
+
+// Cards returns the cards of the deck, not shuffled.
+func (o Options) Cards() []Card {
+	if len(o.Deck) == 0 {
+		return StandardDeck()
+	}
+
+	deck := []Card{}
+	for c := MinCard; c <= MaxCard; c++ {
+		for i := 0; i < o.Deck[c]; i++ {
+			deck = append(deck, c)
+		}
+	}
+
+	return deck
+}
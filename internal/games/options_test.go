@@ -0,0 +1,206 @@
+package games_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options func(o *games.Options)
+		valid   bool
+	}{
+		{"default", func(o *games.Options) {}, true},
+		{"negative timeout", func(o *games.Options) { o.TurnTimeout = -1 }, false},
+		{"negative lobby timeout", func(o *games.Options) { o.LobbyTimeout = -1 }, false},
+		{"zero score limit", func(o *games.Options) { o.ScoreLimit = 0 }, false},
+		{"no initial reveal", func(o *games.Options) { o.InitialReveals = 0 }, true},
+		{"too many initial reveals", func(o *games.Options) { o.InitialReveals = games.Rows*games.Columns + 1 }, false},
+		{"custom deck", func(o *games.Options) { o.Deck = map[games.Card]int{0: 30, 5: 20} }, true},
+		{"deck too small", func(o *games.Options) { o.Deck = map[games.Card]int{0: 49} }, false},
+		{"invalid card", func(o *games.Options) { o.Deck = map[games.Card]int{13: 50} }, false},
+		{"negative card count", func(o *games.Options) { o.Deck = map[games.Card]int{0: 60, 1: -1} }, false},
+	}
+
+	for _, test := range tests {
+		o := games.DefaultOptions()
+		test.options(&o)
+		err := o.Validate(2, 4)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got error %v", test.name, test.valid, err)
+		}
+	}
+
+	// the standard deck deals the boards of up to 12 players
+	for _, test := range []struct {
+		min, max int
+		valid    bool
+	}{
+		{2, 12, true},
+		{2, 13, false},
+		{1, 4, false},
+		{0, 0, false},
+		{3, 2, false},
+		{2, -1, false},
+	} {
+		err := games.DefaultOptions().Validate(test.min, test.max)
+		if (err == nil) != test.valid {
+			t.Errorf("%d to %d players: expected valid %v, got error %v", test.min, test.max, test.valid, err)
+		}
+	}
+}
+
+func TestDeckTooSmall(t *testing.T) {
+	logger := zerolog.Nop()
+
+	// games not validated, eg replayed ones, fail to start rather than panic
+	options := games.DefaultOptions()
+	options.Deck = map[games.Card]int{0: 20}
+	game := games.New(&logger, 2, 2, games.WithOptions(options))
+	for _, pID := range []string{"p1", "p2"} {
+		err := game.AddPlayer(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when adding a player: %v", err)
+		}
+	}
+
+	err := game.Start()
+	if err == nil {
+		t.Fatalf("expected error when starting a game with a deck too small")
+	}
+	if game.IsStarted() {
+		t.Errorf("expected game not to be started")
+	}
+}
+
+func TestOptionsLog(t *testing.T) {
+	logger := zerolog.Nop()
+
+	options := games.DefaultOptions()
+	options.InitialReveals = 3
+	options.Deck = map[games.Card]int{-2: 20, 7: 40}
+
+	game := games.New(&logger, 2, 2, games.WithSeed(5), games.WithOptions(options))
+	for _, pID := range []string{"p1", "p2"} {
+		err := game.AddPlayer(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when adding a player: %v", err)
+		}
+	}
+
+	err := game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error when starting the game: %v", err)
+	}
+
+	for _, pID := range []string{"p1", "p2"} {
+		moves, _ := games.ParseMoves("Ra1 Rb1")
+		err = game.Play(pID, moves...)
+		if err != nil {
+			t.Fatalf("unexpected error when revealing cards: %v", err)
+		}
+
+		err = game.PlayerInit(pID)
+		if err == nil {
+			t.Errorf("expected an error initializing player with only 2 cards revealed")
+		}
+
+		moves, _ = games.ParseMoves("Rc1")
+		err = game.Play(pID, moves...)
+		if err != nil {
+			t.Fatalf("unexpected error when revealing cards: %v", err)
+		}
+
+		err = game.PlayerInit(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when initializing player: %v", err)
+		}
+	}
+
+	board, _ := game.Board("p1")
+	for c := 0; c < games.Columns; c++ {
+		for r := 0; r < games.Rows; r++ {
+			card := board.Slots[c][r].Card
+			if card != -2 && card != 7 {
+				t.Errorf("unexpected card %d dealt from custom deck", card)
+			}
+		}
+	}
+
+	l, err := games.ParseLog(game.Log().String())
+	if err != nil {
+		t.Fatalf("unexpected error parsing log: %v", err)
+	}
+
+	if l.Options == nil || l.Options.InitialReveals != 3 || l.Options.Deck[7] != 40 {
+		t.Errorf("expected options in log, got %v", l.Options)
+	}
+
+	_, err = games.Replay(&logger, l)
+	if err != nil {
+		t.Errorf("unexpected error replaying game with options: %v", err)
+	}
+}
+
+func TestTurnTimeout(t *testing.T) {
+	logger := zerolog.Nop()
+
+	options := games.DefaultOptions()
+	options.TurnTimeout = 1
+
+	game := games.New(&logger, 2, 2, games.WithSeed(7), games.WithOptions(options))
+	timeout := make(chan string, 1)
+	game.OnEvent(func(e games.Event) {
+		if e.Type == "timeout" {
+			timeout <- e.Player
+		}
+	})
+
+	for _, pID := range []string{"p1", "p2"} {
+		err := game.AddPlayer(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when adding a player: %v", err)
+		}
+	}
+
+	err := game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error when starting the game: %v", err)
+	}
+	defer func() {
+		_ = game.Stop()
+	}()
+
+	for _, pID := range []string{"p1", "p2"} {
+		moves, _ := games.ParseMoves("Ra1 Rb1")
+		_ = game.Play(pID, moves...)
+		err = game.PlayerInit(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when initializing player: %v", err)
+		}
+	}
+
+	late := game.CurrentPlayer()
+	deadline := game.TurnDeadline()
+	if deadline.IsZero() || time.Until(deadline) > time.Second {
+		t.Errorf("expected the turn to be played automatically within a second, got deadline %v", deadline)
+	}
+
+	select {
+	case pID := <-timeout:
+		if pID != late {
+			t.Errorf("expected timeout of player %s, got %s", late, pID)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("expected a turn timeout")
+	}
+
+	if game.CurrentPlayer() == late {
+		t.Errorf("expected turn of player %s to be played after timeout", late)
+	}
+}
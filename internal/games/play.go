@@ -0,0 +1,371 @@
+package games
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Play applies the move steps of a player. During the initial reveal phase
+// of a round, players may only reveal cards. During the turn loop, only the
+// current player may play: a draw from the draw pile must be followed by a
+// placement or a discard, a take from the discard pile by a placement.
+// Moves are published on the game topic once applied.
+func (game *Game) Play(pID string, moves ...Move) error {
+	return game.Apply(pID, FormatMoves(moves))
+}
+
+// apply applies move steps and returns the events to publish,
+// starting with the move event of the applied steps.
+func (s *skyjoState) apply(pID string, moves []Move) ([]Event, error) {
+	applied, events, err := s.play(pID, moves)
+
+	if len(applied) > 0 {
+		events = append([]Event{{Type: "move", Player: pID, Data: FormatMoves(applied)}}, events...)
+	}
+
+	return events, err
+}
+
+// play applies move steps and returns the applied moves, completed with
+// their cards, and the events to publish.
+func (s *skyjoState) play(pID string, moves []Move) ([]Move, []Event, error) {
+	applied := []Move{}
+	events := []Event{}
+
+	if !s.game.IsStarted() || s.finished {
+		return applied, events, fmt.Errorf("[%s] game not started", s.game.Name)
+	}
+
+	board, ok := s.boards[pID]
+	if !ok {
+		return applied, events, fmt.Errorf("[%s] unknown player %s", s.game.Name, pID)
+	}
+
+	if s.game.skipped(pID) {
+		return applied, events, fmt.Errorf("[%s] player %s forfeited", s.game.Name, pID)
+	}
+
+	for _, m := range moves {
+		var err error
+		var endOfTurn bool
+
+		if !s.playing {
+			err = s.reveal(board, &m)
+		} else if s.players[s.current] != pID {
+			err = fmt.Errorf("not player %s turn", pID)
+		} else {
+			endOfTurn, err = s.step(board, &m)
+		}
+
+		if err != nil {
+			return applied, events, fmt.Errorf("[%s] invalid move %s: %s", s.game.Name, m, err.Error())
+		}
+
+		s.record(pID, m)
+		applied = append(applied, m)
+
+		if endOfTurn {
+			clears := s.endTurn(pID, board)
+			applied = append(applied, clears...)
+			events = append(events, s.next()...)
+			break
+		}
+	}
+
+	return applied, events, nil
+}
+
+// reveal applies an initial reveal step.
+func (s *skyjoState) reveal(board *Board, m *Move) error {
+	if m.Kind != Reveal {
+		return fmt.Errorf("only reveals are allowed before the first turn")
+	}
+
+	if board.RevealedCount() >= s.options.InitialReveals {
+		return fmt.Errorf("%d cards already revealed", s.options.InitialReveals)
+	}
+
+	slot, err := board.slot(m.Square)
+	if err != nil {
+		return err
+	}
+
+	if slot.Revealed {
+		return fmt.Errorf("square %s already revealed", m.Square)
+	}
+
+	slot.Revealed = true
+	m.Card, m.Known = slot.Card, true
+
+	return nil
+}
+
+// step applies a turn step of the current player, and returns true
+// if the step ends the player's turn.
+func (s *skyjoState) step(board *Board, m *Move) (bool, error) {
+	switch m.Kind {
+	case Draw:
+		if s.holding {
+			return false, fmt.Errorf("a card is already held")
+		}
+		if len(s.deck) == 0 && len(s.discard) < 2 {
+			return false, fmt.Errorf("no card left to draw")
+		}
+		s.held, s.holding, s.heldFromDeck = s.pop(), true, true
+		m.Card, m.Known = s.held, true
+		return false, nil
+	case Take:
+		if s.holding {
+			return false, fmt.Errorf("a card is already held")
+		}
+		if len(s.discard) == 0 {
+			return false, fmt.Errorf("discard pile is empty")
+		}
+		s.held, s.holding, s.heldFromDeck = s.discard[len(s.discard)-1], true, false
+		s.discard = s.discard[:len(s.discard)-1]
+		m.Card, m.Known = s.held, true
+		return false, nil
+	case Place:
+		if !s.holding {
+			return false, fmt.Errorf("no card held")
+		}
+		slot, err := board.slot(m.Square)
+		if err != nil {
+			return false, err
+		}
+		m.Card, m.Known = slot.Card, true
+		s.discard = append(s.discard, slot.Card)
+		slot.Card, slot.Revealed = s.held, true
+		s.holding = false
+		return true, nil
+	case Discard:
+		if !s.holding || !s.heldFromDeck {
+			return false, fmt.Errorf("no card drawn from the draw pile")
+		}
+		slot, err := board.slot(m.Square)
+		if err != nil {
+			return false, err
+		}
+		if slot.Revealed {
+			return false, fmt.Errorf("square %s already revealed", m.Square)
+		}
+		s.discard = append(s.discard, s.held)
+		slot.Revealed = true
+		m.Card, m.Known = slot.Card, true
+		s.holding = false
+		return true, nil
+	default:
+		return false, fmt.Errorf("move not allowed during a turn")
+	}
+}
+
+// record appends a move step of the current round and turn to the game history.
+func (s *skyjoState) record(pID string, m Move) {
+	s.game.record(s.round, s.turn, pID, m)
+}
+
+// endTurn clears the completed columns of the player's board, and checks
+// if the player closes the round. It returns the column clear moves.
+func (s *skyjoState) endTurn(pID string, board *Board) []Move {
+	clears := s.clearColumns(board)
+	for _, m := range clears {
+		s.record(pID, m)
+	}
+	s.discardColumns(clears)
+
+	if s.closer == "" && board.AllRevealed() {
+		s.closer = pID
+		s.lastTurns = len(s.players) - 1
+	} else if s.closer != "" {
+		s.lastTurns--
+	}
+
+	return clears
+}
+
+// clearColumns clears the completed columns of a board, if column clears are enabled.
+func (s *skyjoState) clearColumns(board *Board) []Move {
+	if !s.options.ColumnClears {
+		return []Move{}
+	}
+
+	return board.clearColumns()
+}
+
+// discardColumns puts the cards of cleared columns on the discard pile.
+func (s *skyjoState) discardColumns(clears []Move) {
+	for _, m := range clears {
+		for r := 0; r < Rows; r++ {
+			s.discard = append(s.discard, m.Card)
+		}
+	}
+}
+
+// next moves on to the next turn, or ends the round when the round is closed
+// and every other player has played its last turn. The turns of forfeited
+// players are skipped. It returns the events to publish.
+func (s *skyjoState) next() []Event {
+	for {
+		if s.closer != "" && s.lastTurns == 0 {
+			return s.endRound()
+		}
+
+		s.current = (s.current + 1) % len(s.players)
+		if !s.game.skipped(s.players[s.current]) {
+			break
+		}
+
+		if s.closer != "" {
+			s.lastTurns--
+		}
+	}
+
+	s.turn++
+	s.startTurnTimer()
+
+	return []Event{{Type: "turn", Data: s.players[s.current]}}
+}
+
+// endRound reveals all cards, scores the round and either ends the game
+// or deals a new round. It returns the events to publish.
+func (s *skyjoState) endRound() []Event {
+	roundScores := make(map[string]int)
+	for _, pID := range s.players {
+		board := s.boards[pID]
+		board.revealAll()
+		s.discardColumns(s.clearColumns(board))
+		roundScores[pID] = board.Score()
+	}
+
+	// the player who closed the round doubles its score if it is positive
+	// and not strictly the lowest one.
+	closerScore := roundScores[s.closer]
+	for _, pID := range s.players {
+		if s.options.DoublePenalty && pID != s.closer && roundScores[pID] <= closerScore && closerScore > 0 {
+			roundScores[s.closer] = 2 * closerScore
+			break
+		}
+	}
+
+	over := false
+	for _, pID := range s.players {
+		s.scores[pID] += roundScores[pID]
+		if s.scores[pID] >= s.options.ScoreLimit {
+			over = true
+		}
+	}
+
+	b, _ := json.Marshal(roundScores)
+	events := []Event{{Type: "round", Data: string(b)}}
+
+	if over {
+		s.finished = true
+		s.playing = false
+		return append(events, Event{Type: "end", Data: s.winner()})
+	}
+
+	for i, pID := range s.players {
+		if pID == s.closer {
+			s.current = i
+		}
+	}
+	// the deck dealt the first round, so it deals the next ones too
+	err := s.newRound()
+	if err != nil {
+		s.game.log.Error().Msgf("unable to deal a new round: %s", err.Error())
+		s.finished = true
+		s.playing = false
+		return append(events, Event{Type: "end", Data: s.winner()})
+	}
+	go s.waitAllPlayersInitialized(s.wg)
+
+	return append(events, Event{Type: "rpc", Data: RevealCards})
+}
+
+// winner returns the player with the lowest total score, among the
+// players who did not forfeit.
+func (s *skyjoState) winner() string {
+	winner := ""
+	for _, pID := range s.players {
+		if s.game.HasForfeited(pID) {
+			continue
+		}
+		if winner == "" || s.scores[pID] < s.scores[winner] {
+			winner = pID
+		}
+	}
+
+	return winner
+}
+
+// IsFinished returns true when a player has reached the score limit.
+func (game *Game) IsFinished() bool {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	return game.finished
+}
+
+// Board returns a copy of the board of a player of a Skyjo game.
+func (game *Game) Board(pID string) (Board, error) {
+	var board Board
+
+	err := game.skyjo(func(s *skyjoState) error {
+		b, ok := s.boards[pID]
+		if !ok {
+			return fmt.Errorf("[%s] unknown player %s", game.Name, pID)
+		}
+
+		board = *b
+		return nil
+	})
+
+	return board, err
+}
+
+// record appends a move step to the game history.
+func (game *Game) record(round, turn int, pID string, m Move) {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	n := len(game.history)
+	if n > 0 {
+		last := &game.history[n-1]
+		if last.Round == round && last.Turn == turn && last.Player == pID {
+			last.Moves = append(last.Moves, m)
+			return
+		}
+	}
+
+	game.history = append(game.history, Record{
+		Round:  round,
+		Turn:   turn,
+		Player: pID,
+		Moves:  []Move{m},
+	})
+}
+
+// Log returns the printable history of the game.
+func (game *Game) Log() Log {
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	records := make([]Record, len(game.history))
+	copy(records, game.history)
+
+	seed := game.seed
+	log := Log{
+		ID:      game.ID.String(),
+		Name:    game.Name,
+		Players: append([]string{}, game.players...),
+		Seed:    &seed,
+		Records: records,
+	}
+
+	if !game.Options.IsDefault() {
+		options := game.Options
+		log.Options = &options
+	}
+
+	return log
+}
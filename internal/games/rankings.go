@@ -0,0 +1,78 @@
+package games
+
+import (
+	"sort"
+)
+
+// Ranker is implemented by rulesets ranking the players of a game, eg to
+// rate them once the game is over. The players of games of other rulesets
+// are all tied, but the players who forfeited, who rank last.
+type Ranker interface {
+	// Rank returns the players from best to worst, tied players being grouped.
+	Rank(s State) [][]string
+}
+
+// RankByScore ranks players from best to worst score, tied players being
+// grouped. The lowest score is the best one when lowest is true.
+func RankByScore(players []string, scores map[string]int, lowest bool) [][]string {
+	sorted := append([]string{}, players...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if lowest {
+			return scores[sorted[i]] < scores[sorted[j]]
+		}
+		return scores[sorted[i]] > scores[sorted[j]]
+	})
+
+	ranking := [][]string{}
+	for i, pID := range sorted {
+		if i > 0 && scores[pID] == scores[sorted[i-1]] {
+			ranking[len(ranking)-1] = append(ranking[len(ranking)-1], pID)
+			continue
+		}
+		ranking = append(ranking, []string{pID})
+	}
+
+	return ranking
+}
+
+// Ranking returns the players of a started game from best to worst, tied
+// players being grouped. The players who forfeited are tied last.
+func (game *Game) Ranking() [][]string {
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	if game.state == nil {
+		return [][]string{}
+	}
+
+	players := game.Players()
+	ranking := [][]string{players}
+	ranker, ok := game.rules.(Ranker)
+	if ok {
+		ranking = ranker.Rank(game.state)
+	}
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+
+	result := [][]string{}
+	forfeits := []string{}
+	for _, group := range ranking {
+		left := []string{}
+		for _, pID := range group {
+			if game.forfeits[pID] {
+				forfeits = append(forfeits, pID)
+				continue
+			}
+			left = append(left, pID)
+		}
+		if len(left) > 0 {
+			result = append(result, left)
+		}
+	}
+	if len(forfeits) > 0 {
+		result = append(result, forfeits)
+	}
+
+	return result
+}
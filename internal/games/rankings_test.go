@@ -0,0 +1,30 @@
+package games_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+func TestRankByScore(t *testing.T) {
+	players := []string{"p1", "p2", "p3", "p4"}
+	scores := map[string]int{"p1": 40, "p2": 12, "p3": 40, "p4": 75}
+
+	ranking := games.RankByScore(players, scores, true)
+	expected := [][]string{{"p2"}, {"p1", "p3"}, {"p4"}}
+	if !reflect.DeepEqual(ranking, expected) {
+		t.Errorf("expected lowest scores first %v, got %v", expected, ranking)
+	}
+
+	ranking = games.RankByScore(players, scores, false)
+	expected = [][]string{{"p4"}, {"p1", "p3"}, {"p2"}}
+	if !reflect.DeepEqual(ranking, expected) {
+		t.Errorf("expected highest scores first %v, got %v", expected, ranking)
+	}
+
+	ranking = games.RankByScore([]string{}, scores, true)
+	if len(ranking) != 0 {
+		t.Errorf("expected empty ranking, got %v", ranking)
+	}
+}
@@ -0,0 +1,103 @@
+package games
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// Replay plays a game log again on a new game created with the log seed and
+// players, and returns the replayed game. An error is returned if the log has
+// no seed, or if the replayed moves differ from the logged ones.
+func Replay(l *zerolog.Logger, log *Log) (*Game, error) {
+	if log.Seed == nil {
+		return nil, fmt.Errorf("game log has no seed")
+	}
+
+	opts := []Option{WithSeed(*log.Seed)}
+	if log.Options != nil {
+		opts = append(opts, WithOptions(*log.Options))
+	}
+
+	game := New(l, len(log.Players), len(log.Players), opts...)
+	for _, pID := range log.Players {
+		err := game.AddPlayer(pID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := game.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	// the seat of a player who forfeited was played by a bot
+	// if the player has later records.
+	last := make(map[string]int)
+	for i, r := range log.Records {
+		last[r.Player] = i
+	}
+
+	for i, r := range log.Records {
+		moves := []Move{}
+		forfeit := false
+		for _, m := range r.Moves {
+			if m.Kind == Forfeit {
+				forfeit = true
+				break
+			}
+			if m.Kind != Clear {
+				moves = append(moves, Move{Kind: m.Kind, Square: m.Square})
+			}
+		}
+
+		if len(moves) > 0 {
+			err = game.Play(r.Player, moves...)
+			if err != nil {
+				return nil, fmt.Errorf("round %d turn %d: %s", r.Round, r.Turn, err.Error())
+			}
+		}
+
+		if forfeit {
+			if last[r.Player] > i {
+				err = game.SetBotControlled(r.Player, true)
+				if err != nil {
+					return nil, fmt.Errorf("round %d turn %d: %s", r.Round, r.Turn, err.Error())
+				}
+			}
+
+			err = game.Forfeit(r.Player)
+			if err != nil {
+				return nil, fmt.Errorf("round %d turn %d: %s", r.Round, r.Turn, err.Error())
+			}
+			continue
+		}
+
+		if r.Turn == 0 {
+			err = game.PlayerInit(r.Player)
+			if err != nil {
+				return nil, fmt.Errorf("round %d turn %d: %s", r.Round, r.Turn, err.Error())
+			}
+		}
+	}
+
+	replayed := game.Log().Records
+	if len(replayed) != len(log.Records) {
+		return nil, fmt.Errorf("replayed %d records, expected %d", len(replayed), len(log.Records))
+	}
+
+	for i, r := range log.Records {
+		if len(replayed[i].Moves) != len(r.Moves) {
+			return nil, fmt.Errorf("round %d turn %d: replayed %s, expected %s", r.Round, r.Turn, FormatMoves(replayed[i].Moves), FormatMoves(r.Moves))
+		}
+
+		for j, m := range r.Moves {
+			if replayed[i].Moves[j].Kind != m.Kind || (m.Known && replayed[i].Moves[j].Card != m.Card) {
+				return nil, fmt.Errorf("round %d turn %d: replayed %s, expected %s", r.Round, r.Turn, FormatMoves(replayed[i].Moves), FormatMoves(r.Moves))
+			}
+		}
+	}
+
+	return game, nil
+}
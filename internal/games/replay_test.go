@@ -0,0 +1,178 @@
+package games_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+// newSeededGame starts a seeded game whose players revealed their initial cards.
+func newSeededGame(t *testing.T, seed int64, players ...string) *games.Game {
+	logger := zerolog.Nop()
+
+	game := games.New(&logger, len(players), len(players), games.WithSeed(seed))
+	for _, pID := range players {
+		err := game.AddPlayer(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when adding a player: %v", err)
+		}
+	}
+
+	err := game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error when starting the game: %v", err)
+	}
+
+	for _, pID := range players {
+		moves, _ := games.ParseMoves("Ra1 Rb2")
+		err = game.Play(pID, moves...)
+		if err != nil {
+			t.Fatalf("unexpected error when revealing cards: %v", err)
+		}
+
+		err = game.PlayerInit(pID)
+		if err != nil {
+			t.Fatalf("unexpected error when initializing player: %v", err)
+		}
+	}
+
+	return game
+}
+
+// playTurn draws a card and discards it, revealing the first face-down card
+// of the current player, or places it on the first card left.
+func playTurn(t *testing.T, game *games.Game) {
+	pID := game.CurrentPlayer()
+	if pID == "" {
+		t.Fatal("expected a current player")
+	}
+
+	board, err := game.Board(pID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var hidden, first *games.Square
+	for c := 0; c < games.Columns; c++ {
+		for r := 0; r < games.Rows; r++ {
+			sq := games.Square{Column: c, Row: r}
+			slot := board.Slots[c][r]
+			if slot.Cleared {
+				continue
+			}
+			if first == nil {
+				first = &sq
+			}
+			if hidden == nil && !slot.Revealed {
+				hidden = &sq
+			}
+		}
+	}
+
+	moves := []games.Move{{Kind: games.Draw}, {Kind: games.Place, Square: *first}}
+	if hidden != nil {
+		moves[1] = games.Move{Kind: games.Discard, Square: *hidden}
+	}
+
+	err = game.Play(pID, moves...)
+	if err != nil {
+		t.Fatalf("unexpected error when playing %s: %v", games.FormatMoves(moves), err)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	game1 := newSeededGame(t, 42, "player1", "player2")
+	game2 := newSeededGame(t, 42, "player1", "player2")
+
+	if game1.Name != game2.Name {
+		t.Errorf("expected same names with same seed, got %s and %s", game1.Name, game2.Name)
+	}
+
+	if game1.ID == game2.ID {
+		t.Errorf("expected different IDs with same seed")
+	}
+
+	for _, pID := range []string{"player1", "player2"} {
+		board1, _ := game1.Board(pID)
+		board2, _ := game2.Board(pID)
+		if board1 != board2 {
+			t.Errorf("expected same boards with same seed for %s", pID)
+		}
+	}
+
+	if game1.CurrentPlayer() != game2.CurrentPlayer() {
+		t.Errorf("expected same first player with same seed")
+	}
+
+	if game1.Seed() != 42 {
+		t.Errorf("expected seed 42, got %d", game1.Seed())
+	}
+}
+
+func TestReplay(t *testing.T) {
+	logger := zerolog.Nop()
+
+	game := newSeededGame(t, 7, "player1", "player2", "player3")
+	for i := 0; i < 60 && !game.IsFinished(); i++ {
+		if game.CurrentPlayer() == "" {
+			// a new round started
+			for _, pID := range game.Players() {
+				moves, _ := games.ParseMoves("Ra1 Rb2")
+				_ = game.Play(pID, moves...)
+				_ = game.PlayerInit(pID)
+			}
+		}
+		playTurn(t, game)
+	}
+
+	log := game.Log()
+	if log.Seed == nil || *log.Seed != 7 {
+		t.Errorf("expected log seed 7, got %v", log.Seed)
+	}
+
+	parsed, err := games.ParseLog(log.String())
+	if err != nil {
+		t.Fatalf("unexpected error parsing log: %v", err)
+	}
+
+	replayed, err := games.Replay(&logger, parsed)
+	if err != nil {
+		t.Fatalf("unexpected error replaying log: %v", err)
+	}
+
+	if replayed.Log().Records[len(log.Records)-1].Turn != log.Records[len(log.Records)-1].Turn {
+		t.Errorf("expected replayed game to end at the same turn")
+	}
+
+	for pID, score := range game.Scores() {
+		if replayed.Scores()[pID] != score {
+			t.Errorf("expected replayed score %d for %s, got %d", score, pID, replayed.Scores()[pID])
+		}
+	}
+
+	// a diverging log is detected
+	parsed.Records[0].Moves[0].Card++
+	_, err = games.Replay(&logger, parsed)
+	if err == nil {
+		t.Errorf("expected error when replaying a diverging log")
+	}
+
+	parsed.Seed = nil
+	_, err = games.Replay(&logger, parsed)
+	if err == nil {
+		t.Errorf("expected error when replaying a log without seed")
+	}
+
+	// 0 is a seed like any other
+	parsed, err = games.ParseLog(newSeededGame(t, 0, "player1", "player2").Log().String())
+	if err != nil {
+		t.Fatalf("unexpected error parsing log: %v", err)
+	}
+
+	_, err = games.Replay(&logger, parsed)
+	if err != nil {
+		t.Errorf("unexpected error replaying a log of seed 0: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package games
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// State is the state of a game, owned by its ruleset.
+type State interface{}
+
+// Ruleset defines the rules of a turn-based game hosted by the server. A
+// ruleset creates the state of each game, and is the only one to read and
+// change it. Games serialize the calls to their ruleset.
+type Ruleset interface {
+	// Setup returns the initial state of a started game, and the events to publish.
+	Setup(game *Game) (State, []Event, error)
+	// Legal returns the moves a player may play, in the ruleset notation.
+	Legal(s State, pID string) []string
+	// Apply plays the move of a player, written in the ruleset notation,
+	// and returns the events to publish.
+	Apply(s State, pID string, move string) ([]Event, error)
+	// Score returns the scores of the players, and true when the game is over.
+	Score(s State) (map[string]int, bool)
+	// Redact returns the state of the game as seen by a player.
+	Redact(s State, pID string) (interface{}, error)
+}
+
+// Forfeiter is implemented by rulesets whose games go on when a player
+// forfeits, the turns of the player being skipped unless its seat is
+// controlled by a bot. Games of other rulesets end when a player forfeits.
+type Forfeiter interface {
+	// Forfeit is called when a player forfeits, and returns the events to publish.
+	Forfeit(s State, pID string) ([]Event, error)
+}
+
+var (
+	rulesetsMu sync.Mutex
+	rulesets   = make(map[string]Ruleset)
+)
+
+// Register makes a ruleset available by name, for games created with this
+// type. It panics if a ruleset is already registered with the same name.
+func Register(name string, r Ruleset) {
+	rulesetsMu.Lock()
+	defer rulesetsMu.Unlock()
+
+	_, ok := rulesets[name]
+	if ok {
+		panic(fmt.Sprintf("ruleset %s already registered", name))
+	}
+
+	rulesets[name] = r
+}
+
+// Rulesets returns the sorted names of the registered rulesets.
+func Rulesets() []string {
+	rulesetsMu.Lock()
+	defer rulesetsMu.Unlock()
+
+	names := []string{}
+	for name := range rulesets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ruleset returns the ruleset registered with a name, or nil.
+func ruleset(name string) Ruleset {
+	rulesetsMu.Lock()
+	defer rulesetsMu.Unlock()
+
+	return rulesets[name]
+}
+
+// WithType sets the type of the game, the name of its ruleset.
+// Games are Skyjo games by default.
+func WithType(name string) Option {
+	return func(game *Game) {
+		game.Type = name
+	}
+}
+
+// Apply plays the move of a player, written in the notation of the game ruleset.
+func (game *Game) Apply(pID, move string) error {
+	game.stateMu.Lock()
+	if game.state == nil {
+		game.stateMu.Unlock()
+		return fmt.Errorf("[%s] game not started", game.Name)
+	}
+
+	if game.IsFinished() {
+		game.stateMu.Unlock()
+		return fmt.Errorf("[%s] game over", game.Name)
+	}
+
+	events, err := game.rules.Apply(game.state, pID, move)
+	_, over := game.rules.Score(game.state)
+	game.stateMu.Unlock()
+
+	if over {
+		game.mu.Lock()
+		if !game.finished {
+			game.finished = true
+			game.endTime = time.Now()
+		}
+		game.mu.Unlock()
+	}
+
+	for _, e := range events {
+		game.emit(e)
+	}
+
+	return err
+}
+
+// Legal returns the moves a player may play, in the notation of the game ruleset.
+func (game *Game) Legal(pID string) []string {
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	if game.state == nil {
+		return []string{}
+	}
+
+	return game.rules.Legal(game.state, pID)
+}
+
+// State returns the state of the game as seen by a player.
+func (game *Game) State(pID string) (interface{}, error) {
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	if game.state == nil {
+		return nil, fmt.Errorf("[%s] game not started", game.Name)
+	}
+
+	return game.rules.Redact(game.state, pID)
+}
+
+// Scores returns the total scores of the players.
+func (game *Game) Scores() map[string]int {
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	if game.state == nil {
+		return make(map[string]int)
+	}
+
+	scores, _ := game.rules.Score(game.state)
+
+	return scores
+}
@@ -0,0 +1,92 @@
+package games_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+func TestRulesets(t *testing.T) {
+	if !utils.ContainsString(games.Rulesets(), games.Skyjo) {
+		t.Errorf("expected skyjo ruleset to be registered, got %v", games.Rulesets())
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected a panic registering a ruleset twice")
+			}
+		}()
+		games.Register(games.Skyjo, nil)
+	}()
+
+	logger := zerolog.Nop()
+	game := games.New(&logger, 1, 1, games.WithType("fake"))
+	_ = game.AddPlayer("p1")
+	err := game.Start()
+	if err == nil {
+		t.Errorf("expected an error starting a game of unknown type")
+	}
+}
+
+func TestSkyjoRuleset(t *testing.T) {
+	game := newSeededGame(t, 42, "p1", "p2")
+
+	pID := game.CurrentPlayer()
+	legal := game.Legal(pID)
+	if len(legal) == 0 {
+		t.Fatalf("expected legal moves")
+	}
+
+	// legal moves are listed in the order of their notation, not ranked
+	if legal[0] != "D" || legal[1] != "T>a1" || legal[len(legal)-1] != "T>d3" {
+		t.Errorf("expected draw then takes square by square, got %v", legal)
+	}
+
+	err := game.Apply(pID, "X")
+	if err == nil {
+		t.Errorf("expected an error applying an invalid move")
+	}
+
+	err = game.Apply(pID, legal[0])
+	if err != nil {
+		t.Errorf("unexpected error applying legal move %s: %v", legal[0], err)
+	}
+
+	state, err := game.State(pID)
+	if err != nil {
+		t.Fatalf("unexpected error getting the game state: %v", err)
+	}
+
+	v, ok := state.(games.View)
+	if !ok || v.Player != pID {
+		t.Errorf("expected the view of player %s, got %v", pID, state)
+	}
+}
+
+func TestSkyjoLegalWithoutHints(t *testing.T) {
+	logger := zerolog.Nop()
+	game := games.New(&logger, 2, 2, games.WithoutHints())
+	for _, pID := range []string{"p1", "p2"} {
+		_ = game.AddPlayer(pID)
+	}
+
+	err := game.Start()
+	if err != nil {
+		t.Fatalf("unexpected error when starting the game: %v", err)
+	}
+
+	// players may not ask for hints, but their legal moves are still listed
+	legal := game.Legal("p1")
+	if len(legal) != 12 || legal[0] != "Ra1" || legal[11] != "Rd3" {
+		t.Errorf("expected reveals of the 12 squares, got %v", legal)
+	}
+
+	_, err = game.Suggest("p1")
+	if err == nil {
+		t.Errorf("expected an error suggesting a move with hints disabled")
+	}
+}
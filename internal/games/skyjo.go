@@ -0,0 +1,177 @@
+package games
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Skyjo is the type of Skyjo games, the default game type.
+const Skyjo string = "skyjo"
+
+func init() {
+	Register(Skyjo, skyjo{})
+}
+
+// skyjo is the Skyjo ruleset.
+type skyjo struct{}
+
+// skyjoState is the state of a Skyjo game. Like any ruleset state, it is
+// only read and changed with the state lock of its game held.
+type skyjoState struct {
+	game            *Game
+	players         []string
+	options         Options
+	rand            *rand.Rand
+	deck            []Card
+	discard         []Card
+	boards          map[string]*Board
+	held            Card
+	holding         bool
+	heldFromDeck    bool
+	playing         bool
+	finished        bool
+	round           int
+	turn            int
+	current         int
+	closer          string
+	lastTurns       int
+	scores          map[string]int
+	playerAnswerMap map[string]bool
+	wg              *sync.WaitGroup
+	turnTimer       *time.Timer
+	turnDeadline    time.Time
+}
+
+// Setup deals the first round and asks players to reveal their initial cards.
+// The cards are shuffled with the game seed, so that games created with the
+// same seed are dealt the same cards.
+func (skyjo) Setup(game *Game) (State, []Event, error) {
+	s := &skyjoState{
+		game:    game,
+		players: game.Players(),
+		options: game.Options,
+		rand:    rand.New(rand.NewSource(game.Seed())),
+		boards:  make(map[string]*Board),
+		scores:  make(map[string]int),
+	}
+	err := s.newRound()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// wait for all players to initialize
+	go s.waitAllPlayersInitialized(s.wg)
+
+	return s, []Event{{Type: "rpc", Data: RevealCards}}, nil
+}
+
+// Legal returns the moves of a player, in the order of their notation.
+func (skyjo) Legal(st State, pID string) []string {
+	s := st.(*skyjoState)
+
+	moves := []string{}
+	v, err := s.view(pID)
+	if err != nil || v.Finished || (v.Playing && v.Current != pID) || s.game.skipped(pID) {
+		return moves
+	}
+
+	for _, m := range Legal(v) {
+		moves = append(moves, FormatMoves(m))
+	}
+
+	return moves
+}
+
+// Apply plays move steps written in the Skyjo moves notation.
+func (skyjo) Apply(st State, pID string, move string) ([]Event, error) {
+	moves, err := ParseMoves(move)
+	if err != nil {
+		return nil, err
+	}
+
+	return st.(*skyjoState).apply(pID, moves)
+}
+
+// Init acknowledges that a player has revealed its initial cards.
+func (skyjo) Init(st State, pID string) ([]Event, error) {
+	return st.(*skyjoState).init(pID)
+}
+
+// Hints returns the legal moves of a player, best first.
+func (skyjo) Hints(st State, pID string) ([]Hint, error) {
+	s := st.(*skyjoState)
+
+	v, err := s.view(pID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !v.Started || v.Finished {
+		return nil, fmt.Errorf("[%s] game not started", s.game.Name)
+	}
+
+	if v.Playing && v.Current != pID {
+		return nil, fmt.Errorf("[%s] not player %s turn", s.game.Name, pID)
+	}
+
+	return Suggest(v), nil
+}
+
+// Turn returns the current player and the deadline of its turn.
+func (skyjo) Turn(st State) (string, time.Time) {
+	s := st.(*skyjoState)
+
+	if !s.playing || s.finished {
+		return "", time.Time{}
+	}
+
+	return s.players[s.current], s.turnDeadline
+}
+
+// Forfeit records the forfeit of a player, and skips its turns.
+func (skyjo) Forfeit(st State, pID string) ([]Event, error) {
+	return st.(*skyjoState).forfeit(pID), nil
+}
+
+// Score returns the total scores of the players.
+func (skyjo) Score(st State) (map[string]int, bool) {
+	s := st.(*skyjoState)
+
+	scores := make(map[string]int)
+	for pID, score := range s.scores {
+		scores[pID] = score
+	}
+
+	return scores, s.finished
+}
+
+// Rank ranks the players from the lowest to the highest total score.
+func (skyjo) Rank(st State) [][]string {
+	s := st.(*skyjoState)
+
+	return RankByScore(s.players, s.scores, true)
+}
+
+// Redact returns the view of a player.
+func (skyjo) Redact(st State, pID string) (interface{}, error) {
+	return st.(*skyjoState).view(pID)
+}
+
+// skyjo calls a function with the state of a started Skyjo game, with the
+// state lock held.
+func (game *Game) skyjo(f func(s *skyjoState) error) error {
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	s, ok := game.state.(*skyjoState)
+	if !ok {
+		if game.state == nil {
+			return fmt.Errorf("[%s] game not started", game.Name)
+		}
+		return fmt.Errorf("[%s] not supported by %s games", game.Name, game.Type)
+	}
+
+	return f(s)
+}
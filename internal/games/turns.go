@@ -2,27 +2,111 @@ package games
 
 import (
 	"fmt"
+	"sync"
 	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 )
 
-// wait for all players to initialize
-func (game *Game) waitAllPlayersInitialized() {
-	var done = make(chan struct{})
+// Initializer is implemented by rulesets whose players acknowledge the setup
+// of the game before the first turn, eg once they revealed their initial cards.
+type Initializer interface {
+	// Init acknowledges the setup of a player, and returns the events to publish.
+	Init(s State, pID string) ([]Event, error)
+}
+
+// Turner is implemented by rulesets whose players play in turn.
+type Turner interface {
+	// Turn returns the player whose turn it is, or an empty string, and the
+	// time its turn is played automatically, or the zero time.
+	Turn(s State) (string, time.Time)
+}
+
+// newRound deals the cards of a new round and resets the players
+// initialization state. An error is returned if the deck is too small
+// to deal the boards.
+func (s *skyjoState) newRound() error {
+	s.round++
+	s.turn = 0
+	s.playing = false
+	s.closer = ""
+	s.holding = false
+	err := s.deal()
+	if err != nil {
+		return err
+	}
+
+	// forfeited players are not waited for, and each round has its own
+	// wait group, as the one of the previous round may still be waited on.
+	s.wg = &sync.WaitGroup{}
+	s.playerAnswerMap = make(map[string]bool)
+	for _, pID := range s.players {
+		if s.game.skipped(pID) {
+			s.playerAnswerMap[pID] = true
+			continue
+		}
+		s.wg.Add(1)
+		s.playerAnswerMap[pID] = false
+	}
+
+	return nil
+}
 
-	game.log.Info().Msgf("[%s] wait all players to initialize", game.Name)
+// deal shuffles a new deck, deals a board to each player and
+// turns the first card of the discard pile over. An error is returned
+// if the deck is too small to deal the boards.
+func (s *skyjoState) deal() error {
+	s.deck = s.options.Cards()
+	if needed := len(s.players)*Rows*Columns + 1; len(s.deck) < needed {
+		return fmt.Errorf("deck of %d cards too small for %d players, %d cards needed", len(s.deck), len(s.players), needed)
+	}
+	shuffle(s.rand, s.deck)
+
+	for _, pID := range s.players {
+		board := &Board{}
+		for c := 0; c < Columns; c++ {
+			for r := 0; r < Rows; r++ {
+				board.Slots[c][r].Card = s.pop()
+			}
+		}
+		s.boards[pID] = board
+	}
+
+	s.discard = []Card{s.pop()}
+
+	return nil
+}
 
-	game.playerAnswerMap = make(map[string]bool)
-	for _, pID := range game.Players() {
-		game.wg.Add(1)
-		game.playerAnswerMap[pID] = false
+// pop removes the top card of the draw pile. When the draw pile is empty,
+// the discard pile but its top card is shuffled to make a new draw pile.
+func (s *skyjoState) pop() Card {
+	if len(s.deck) == 0 && len(s.discard) > 1 {
+		top := s.discard[len(s.discard)-1]
+		s.deck = s.discard[:len(s.discard)-1]
+		s.discard = []Card{top}
+		shuffle(s.rand, s.deck)
 	}
 
+	card := s.deck[len(s.deck)-1]
+	s.deck = s.deck[:len(s.deck)-1]
+
+	return card
+}
+
+// wait for all players of the round of a wait group to initialize, the
+// turn loop is started by the last player initialization.
+func (s *skyjoState) waitAllPlayersInitialized(wg *sync.WaitGroup) {
+	var done = make(chan struct{})
+	game := s.game
+
+	game.log.Info().Msg("wait all players to initialize")
+
 	// Will automatically close done channel and end select after all RPC have been called.
 	go func() {
-		game.log.Debug().Msgf("[%s] waiting...", game.Name)
+		game.log.Debug().Msg("waiting...")
 		defer close(done)
-		game.wg.Wait()
-		game.log.Debug().Msgf("[%s] WaitGroup done !", game.Name)
+		wg.Wait()
+		game.log.Debug().Msg("WaitGroup done !")
 	}()
 
 	// Blocks until done channel is closed, or timeout occurs.
@@ -31,31 +115,235 @@ func (game *Game) waitAllPlayersInitialized() {
 	case <-done:
 		game.log.Debug().Msgf("channel closed")
 		game.log.Debug().Msgf("exit waitForInitRPC")
-		go game.startTurnLoop()
 		return
 	case <-time.After(game.waitForRPCTimeout):
 		game.log.Debug().Msgf("** timeout **")
 		game.log.Debug().Msgf("exit waitForInitRPC")
+
+		// publish a timeout event for each player who did not answer,
+		// so that their seat may be handed to a bot.
+		game.stateMu.Lock()
+		late := []string{}
+		if game.state == s && s.wg == wg && game.IsStarted() {
+			for _, pID := range s.players {
+				if !s.playerAnswerMap[pID] {
+					late = append(late, pID)
+				}
+			}
+		}
+		game.stateMu.Unlock()
+
+		for _, pID := range late {
+			game.emit(Event{Type: "timeout", Player: pID, Data: "init"})
+		}
 		// TODO handle game termination
 	}
 
 }
 
-func (game *Game) startTurnLoop() {
-	game.log.Info().Msgf("[%s] enter turn loop", game.Name)
-	// TODO: here, initialize turn
+// initialized returns true when all players revealed their initial cards.
+func (s *skyjoState) initialized() bool {
+	for _, a := range s.playerAnswerMap {
+		if !a {
+			return false
+		}
+	}
+
+	return true
+}
+
+// startTurnLoop starts the turns of the round and returns the ID of the
+// first player.
+func (s *skyjoState) startTurnLoop() string {
+	s.game.log.Info().Msg("enter turn loop")
+
+	// the first round is opened by the player with the highest revealed cards,
+	// next rounds by the player who closed the previous one.
+	if s.round == 1 {
+		best, found := 0, false
+		for i, pID := range s.players {
+			if s.game.skipped(pID) {
+				continue
+			}
+			sum := s.boards[pID].RevealedSum()
+			if !found || sum > best {
+				best, found = sum, true
+				s.current = i
+			}
+		}
+	}
+	for s.game.skipped(s.players[s.current]) {
+		s.current = (s.current + 1) % len(s.players)
+	}
+	s.playing = true
+	s.turn = 1
+	s.startTurnTimer()
+
+	return s.players[s.current]
+}
+
+// startTurnTimer arms the timeout of the current turn, if any.
+func (s *skyjoState) startTurnTimer() {
+	if s.options.TurnTimeout == 0 {
+		return
+	}
+
+	if s.turnTimer != nil {
+		s.turnTimer.Stop()
+	}
+
+	round, turn := s.round, s.turn
+	s.turnDeadline = time.Now().Add(time.Duration(s.options.TurnTimeout) * time.Second)
+	s.turnTimer = time.AfterFunc(time.Duration(s.options.TurnTimeout)*time.Second, func() {
+		s.turnTimeout(round, turn)
+	})
+}
+
+// CurrentPlayer returns the ID of the player whose turn it is, or an empty
+// string outside of the turn loop or when players do not play in turn.
+func (game *Game) CurrentPlayer() string {
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	turner, ok := game.rules.(Turner)
+	if !ok || game.state == nil || !game.IsStarted() || game.IsFinished() {
+		return ""
+	}
+
+	current, _ := turner.Turn(game.state)
+
+	return current
 }
 
-// PlayerInit -.
+// TurnDeadline returns the time the current turn is played automatically,
+// or the zero time when the turn has no timeout.
+func (game *Game) TurnDeadline() time.Time {
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	turner, ok := game.rules.(Turner)
+	if !ok || game.state == nil || !game.IsStarted() || game.IsFinished() {
+		return time.Time{}
+	}
+
+	_, deadline := turner.Turn(game.state)
+
+	return deadline
+}
+
+// hint returns the best hinted moves of a player if the given turn of the
+// game is being played, or false.
+func (s *skyjoState) hint(pID string, round, turn int) ([]Move, bool, error) {
+	game := s.game
+
+	game.stateMu.Lock()
+	defer game.stateMu.Unlock()
+
+	if game.state != s || !game.IsStarted() || game.IsFinished() || !s.playing || s.round != round || s.turn != turn {
+		return nil, false, nil
+	}
+
+	v, err := s.view(pID)
+	if err != nil {
+		return nil, true, err
+	}
+
+	hints := Suggest(v)
+	if len(hints) == 0 {
+		return nil, true, fmt.Errorf("no move for player %s", pID)
+	}
+
+	moves, err := ParseMoves(hints[0].Moves)
+
+	return moves, true, err
+}
+
+// turnTimeout plays the turn of a player who did not play in time with the
+// best hinted moves, then publishes a timeout event.
+func (s *skyjoState) turnTimeout(round, turn int) {
+	game := s.game
+
+	game.stateMu.Lock()
+	pID := s.players[s.current]
+	game.stateMu.Unlock()
+
+	moves, ok, err := s.hint(pID, round, turn)
+	if !ok {
+		return
+	}
+
+	for ok {
+		if err == nil {
+			err = game.Play(pID, moves...)
+		}
+		if err != nil {
+			game.log.Error().Str(logging.PlayerID, pID).Msgf("turn timeout: %s", err.Error())
+			return
+		}
+
+		moves, ok, err = s.hint(pID, round, turn)
+	}
+
+	game.log.Info().Str(logging.PlayerID, pID).Msg("turn played after timeout")
+	game.emit(Event{Type: "timeout", Player: pID, Data: "turn"})
+}
+
+// PlayerInit acknowledges that a player is set up, eg has revealed its
+// initial cards. When all players are initialized, the turn loop starts.
 func (game *Game) PlayerInit(pID string) error {
-	if !game.started {
+	initializer, ok := game.rules.(Initializer)
+	if !ok {
+		return fmt.Errorf("[%s] not supported by %s games", game.Name, game.Type)
+	}
+
+	game.stateMu.Lock()
+	if game.state == nil {
+		game.stateMu.Unlock()
 		return fmt.Errorf("[%s] game not started", game.Name)
 	}
 
-	if !game.playerAnswerMap[pID] {
-		game.playerAnswerMap[pID] = true
-		game.wg.Done()
+	events, err := initializer.Init(game.state, pID)
+	game.stateMu.Unlock()
+
+	for _, e := range events {
+		game.emit(e)
+	}
+
+	return err
+}
+
+// init acknowledges that a player has revealed its initial cards, and starts
+// the turn loop once all players are initialized.
+func (s *skyjoState) init(pID string) ([]Event, error) {
+	game := s.game
+
+	if !game.IsStarted() {
+		return nil, fmt.Errorf("[%s] game not started", game.Name)
 	}
 
-	return nil
+	answered, ok := s.playerAnswerMap[pID]
+	if !ok {
+		return nil, fmt.Errorf("[%s] unknown player %s", game.Name, pID)
+	}
+
+	if game.skipped(pID) {
+		return nil, fmt.Errorf("[%s] player %s forfeited", game.Name, pID)
+	}
+
+	if s.boards[pID].RevealedCount() < s.options.InitialReveals {
+		return nil, fmt.Errorf("[%s] player %s must reveal %d cards first", game.Name, pID, s.options.InitialReveals)
+	}
+
+	if answered {
+		return nil, nil
+	}
+
+	s.playerAnswerMap[pID] = true
+	s.wg.Done()
+
+	if !s.initialized() {
+		return nil, nil
+	}
+
+	return []Event{{Type: "turn", Data: s.startTurnLoop()}}, nil
 }
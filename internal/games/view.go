@@ -0,0 +1,112 @@
+package games
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// View is the state of a game as seen by a player: face-down cards of all
+// boards are hidden, and the held card is only shown to the current player.
+type View struct {
+	Player       string           `json:"player"`
+	Players      []string         `json:"players"`
+	Boards       map[string]Board `json:"boards"`
+	Discard      Card             `json:"discard"`
+	HasDiscard   bool             `json:"hasDiscard"`
+	DeckSize     int              `json:"deckSize"`
+	Held         Card             `json:"held"`
+	Holding      bool             `json:"holding"`
+	HeldFromDeck bool             `json:"heldFromDeck"`
+	Started      bool             `json:"started"`
+	Initialized  bool             `json:"initialized"`
+	Playing      bool             `json:"playing"`
+	Finished     bool             `json:"finished"`
+	Current      string           `json:"current"`
+	Round        int              `json:"round"`
+	Turn         int              `json:"turn"`
+	Scores       map[string]int   `json:"scores"`
+	Bots         map[string]bool  `json:"bots"`
+	Forfeits     map[string]bool  `json:"forfeits"`
+	Options      Options          `json:"options"`
+}
+
+// View returns the state of a Skyjo game as seen by a player.
+func (game *Game) View(pID string) (View, error) {
+	state, err := game.State(pID)
+	if err != nil {
+		return View{}, err
+	}
+
+	v, ok := state.(View)
+	if !ok {
+		return View{}, fmt.Errorf("[%s] not supported by %s games", game.Name, game.Type)
+	}
+
+	return v, nil
+}
+
+// view returns the state of the game as seen by a player.
+func (s *skyjoState) view(pID string) (View, error) {
+	if !utils.ContainsString(s.players, pID) {
+		return View{}, fmt.Errorf("[%s] unknown player %s", s.game.Name, pID)
+	}
+
+	finished := s.finished || s.game.IsFinished()
+	v := View{
+		Player:      pID,
+		Players:     append([]string{}, s.players...),
+		Boards:      make(map[string]Board),
+		DeckSize:    len(s.deck),
+		Started:     s.game.IsStarted(),
+		Initialized: s.playerAnswerMap[pID],
+		Playing:     s.playing && !finished,
+		Finished:    finished,
+		Round:       s.round,
+		Turn:        s.turn,
+		Scores:      make(map[string]int),
+		Bots:        make(map[string]bool),
+		Forfeits:    make(map[string]bool),
+		Options:     s.options,
+	}
+
+	for id, board := range s.boards {
+		redacted := *board
+		for c := 0; c < Columns; c++ {
+			for r := 0; r < Rows; r++ {
+				if !redacted.Slots[c][r].Revealed {
+					redacted.Slots[c][r].Card = 0
+				}
+			}
+		}
+		v.Boards[id] = redacted
+	}
+
+	if len(s.discard) > 0 {
+		v.Discard, v.HasDiscard = s.discard[len(s.discard)-1], true
+	}
+
+	if v.Playing {
+		v.Current = s.players[s.current]
+		if v.Current == pID {
+			v.Held, v.Holding, v.HeldFromDeck = s.held, s.holding, s.heldFromDeck
+		}
+	}
+
+	for id, score := range s.scores {
+		v.Scores[id] = score
+	}
+
+	s.game.mu.Lock()
+	defer s.game.mu.Unlock()
+
+	for id, bot := range s.game.bots {
+		v.Bots[id] = bot
+	}
+
+	for id, forfeit := range s.game.forfeits {
+		v.Forfeits[id] = forfeit
+	}
+
+	return v, nil
+}
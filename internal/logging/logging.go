@@ -0,0 +1,131 @@
+// Package logging sets up the loggers of the game server once, from its
+// configuration: console or JSON output, a default level, and a level per
+// component. Components derive their logger from the root logger with
+// Component, and attach the game, player and client IDs as fields rather
+// than in their messages.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Output formats.
+const (
+	Console string = "console"
+	JSON    string = "json"
+)
+
+// Fields attached to the log entries.
+const (
+	ComponentField string = "component"
+	GameID         string = "game_id"
+	PlayerID       string = "player_id"
+	ClientID       string = "client_id"
+	Channel        string = "channel"
+)
+
+// Config is the logging configuration.
+type Config struct {
+	Format string    // Format is console (default) or json.
+	Level  string    // Level is the default level, info if empty.
+	Levels string    // Levels are the levels of components, eg "manager=debug,game=warn".
+	Output io.Writer // Output is stderr if nil.
+	Stream *Stream   // Stream, if any, is also written the JSON log entries.
+}
+
+var (
+	mu     sync.RWMutex
+	levels = map[string]zerolog.Level{}
+)
+
+// New returns the root logger set up from a configuration, and records the
+// levels of the components.
+func New(cfg Config) (zerolog.Logger, error) {
+	out := cfg.Output
+	if out == nil {
+		out = os.Stderr
+	}
+
+	level := zerolog.InfoLevel
+	if cfg.Level != "" {
+		l, err := zerolog.ParseLevel(cfg.Level)
+		if err != nil {
+			return zerolog.Nop(), fmt.Errorf("invalid log level %q: %s", cfg.Level, err.Error())
+		}
+		level = l
+	}
+
+	componentLevels, err := ParseLevels(cfg.Levels)
+	if err != nil {
+		return zerolog.Nop(), err
+	}
+
+	switch cfg.Format {
+	case "", Console:
+		out = zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339}
+	case JSON:
+	default:
+		return zerolog.Nop(), fmt.Errorf("invalid log format %q, expected %s or %s", cfg.Format, Console, JSON)
+	}
+
+	if cfg.Stream != nil {
+		out = zerolog.MultiLevelWriter(out, cfg.Stream)
+	}
+
+	// levels are filtered by each logger, the global level lets them all through
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
+
+	mu.Lock()
+	levels = componentLevels
+	mu.Unlock()
+
+	return zerolog.New(out).Level(level).With().Timestamp().Logger(), nil
+}
+
+// ParseLevels parses the levels of components, eg "manager=debug,game=warn".
+func ParseLevels(s string) (map[string]zerolog.Level, error) {
+	componentLevels := map[string]zerolog.Level{}
+	if strings.TrimSpace(s) == "" {
+		return componentLevels, nil
+	}
+
+	for _, setting := range strings.Split(s, ",") {
+		component, name, ok := strings.Cut(strings.TrimSpace(setting), "=")
+		if !ok || component == "" {
+			return nil, fmt.Errorf("invalid component log level %q, expected component=level", setting)
+		}
+
+		level, err := zerolog.ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q of component %s: %s", name, component, err.Error())
+		}
+		componentLevels[component] = level
+	}
+
+	return componentLevels, nil
+}
+
+// Component returns the logger of a component, with the component field,
+// at the level set for the component, or at the level of the root logger.
+// The logger must be the root logger: a logger derived from another
+// component logger would carry two component fields.
+func Component(l *zerolog.Logger, name string) zerolog.Logger {
+	logger := l.With().Str(ComponentField, name).Logger()
+
+	mu.RLock()
+	level, ok := levels[name]
+	mu.RUnlock()
+
+	if ok {
+		logger = logger.Level(level)
+	}
+
+	return logger
+}
@@ -0,0 +1,81 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+)
+
+func TestParseLevels(t *testing.T) {
+	levels, err := logging.ParseLevels("manager=debug, game=warn")
+	if err != nil {
+		t.Fatalf("unexpected error parsing levels: %v", err)
+	}
+	if levels["manager"] != zerolog.DebugLevel || levels["game"] != zerolog.WarnLevel {
+		t.Errorf("expected manager debug and game warn levels, got %v", levels)
+	}
+
+	for _, s := range []string{"manager", "=debug", "manager=loud"} {
+		_, err = logging.ParseLevels(s)
+		if err == nil {
+			t.Errorf("expected an error parsing levels %q", s)
+		}
+	}
+}
+
+func TestJSONComponents(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := logging.New(logging.Config{Format: logging.JSON, Level: "info", Levels: "manager=debug,game=error", Output: &out})
+	if err != nil {
+		t.Fatalf("unexpected error setting logging up: %v", err)
+	}
+
+	manager := logging.Component(&logger, "manager")
+	manager.Debug().Str(logging.GameID, "g1").Msg("debug manager")
+
+	game := logging.Component(&logger, "game")
+	game.Info().Msg("info game")
+	game.Error().Msg("error game")
+
+	memory := logging.Component(&logger, "memory")
+	memory.Debug().Msg("debug memory")
+	memory.Info().Msg("info memory")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	messages := []string{}
+	for _, line := range lines {
+		var entry map[string]interface{}
+		err = json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			t.Fatalf("expected JSON log entry, got %q", line)
+		}
+		messages = append(messages, entry["message"].(string))
+
+		if entry["message"] == "debug manager" && (entry[logging.ComponentField] != "manager" || entry[logging.GameID] != "g1") {
+			t.Errorf("expected component and game fields, got %v", entry)
+		}
+	}
+
+	expected := "debug manager,error game,info memory"
+	if strings.Join(messages, ",") != expected {
+		t.Errorf("expected messages %s, got %v", expected, messages)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, cfg := range []logging.Config{
+		{Format: "xml"},
+		{Level: "loud"},
+		{Levels: "manager"},
+	} {
+		_, err := logging.New(cfg)
+		if err == nil {
+			t.Errorf("expected an error setting logging up with %+v", cfg)
+		}
+	}
+}
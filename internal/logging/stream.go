@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultStreamSize is the number of log entries buffered by a stream.
+const DefaultStreamSize = 1024
+
+// Stream is a writer of JSON log entries which buffers the entries at or
+// above its level, of its components, until they are published. When the
+// buffer is full, the entries are dropped rather than blocking the loggers.
+type Stream struct {
+	level      zerolog.Level
+	components map[string]bool
+	entries    chan []byte
+	dropped    atomic.Uint64
+}
+
+// NewStream creates a stream of the entries at or above a level, of a comma
+// separated list of components, or of all of them if the list is empty,
+// buffering up to size entries.
+func NewStream(level, components string, size int) (*Stream, error) {
+	l := zerolog.InfoLevel
+	if level != "" {
+		var err error
+		l, err = zerolog.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stream log level %q: %s", level, err.Error())
+		}
+	}
+
+	if size <= 0 {
+		size = DefaultStreamSize
+	}
+
+	s := &Stream{
+		level:      l,
+		components: map[string]bool{},
+		entries:    make(chan []byte, size),
+	}
+	for _, component := range strings.Split(components, ",") {
+		component = strings.TrimSpace(component)
+		if component != "" {
+			s.components[component] = true
+		}
+	}
+
+	return s, nil
+}
+
+// Write buffers a log entry if it passes the filters of the stream.
+func (s *Stream) Write(p []byte) (int, error) {
+	var entry struct {
+		Level     string `json:"level"`
+		Component string `json:"component"`
+	}
+	err := json.Unmarshal(p, &entry)
+	if err != nil {
+		// not a log entry
+		return len(p), nil
+	}
+
+	level, err := zerolog.ParseLevel(entry.Level)
+	if err != nil || level < s.level {
+		return len(p), nil
+	}
+	if len(s.components) != 0 && !s.components[entry.Component] {
+		return len(p), nil
+	}
+
+	// the loggers reuse their buffers
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	select {
+	case s.entries <- data:
+	default:
+		s.dropped.Add(1)
+	}
+
+	return len(p), nil
+}
+
+// Dropped returns the number of entries dropped because the buffer was full
+// or their publication failed.
+func (s *Stream) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Run publishes the buffered entries until done is closed. Publication
+// errors are not logged, which would feed the stream with its own errors.
+func (s *Stream) Run(done <-chan struct{}, publish func(data []byte) error) {
+	for {
+		select {
+		case <-done:
+			return
+		case data := <-s.entries:
+			err := publish(data)
+			if err != nil {
+				s.dropped.Add(1)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package logging_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+)
+
+func TestStream(t *testing.T) {
+	stream, err := logging.NewStream("warn", "game", 2)
+	if err != nil {
+		t.Fatalf("unexpected error creating stream: %v", err)
+	}
+
+	var out bytes.Buffer
+	logger, err := logging.New(logging.Config{Format: logging.JSON, Level: "debug", Output: &out, Stream: stream})
+	if err != nil {
+		t.Fatalf("unexpected error setting logging up: %v", err)
+	}
+
+	game := logging.Component(&logger, "game")
+	game.Info().Msg("info game")
+	game.Warn().Msg("warn game 1")
+	manager := logging.Component(&logger, "manager")
+	manager.Error().Msg("error manager")
+	game.Error().Msg("error game 2")
+	// the buffer is full
+	game.Error().Msg("error game 3")
+
+	if stream.Dropped() != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", stream.Dropped())
+	}
+	if bytes.Count(out.Bytes(), []byte("\n")) != 5 {
+		t.Errorf("expected all entries to be logged, got %q", out.String())
+	}
+
+	done := make(chan struct{})
+	published := []string{}
+	stream.Run(done, func(data []byte) error {
+		published = append(published, string(data))
+		if len(published) == 2 {
+			close(done)
+		}
+		return nil
+	})
+
+	if len(published) != 2 || !bytes.Contains([]byte(published[0]), []byte("warn game 1")) || !bytes.Contains([]byte(published[1]), []byte("error game 2")) {
+		t.Errorf("expected game warnings and errors to be published, got %v", published)
+	}
+
+	// failed publications are dropped
+	game.Error().Msg("error game 4")
+	done = make(chan struct{})
+	stream.Run(done, func(data []byte) error {
+		close(done)
+		return fmt.Errorf("no subscriber")
+	})
+	if stream.Dropped() != 2 {
+		t.Errorf("expected 2 dropped entries, got %d", stream.Dropped())
+	}
+
+	_, err = logging.NewStream("loud", "", 0)
+	if err == nil {
+		t.Errorf("expected an error creating a stream with an invalid level")
+	}
+}
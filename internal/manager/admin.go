@@ -0,0 +1,326 @@
+package manager
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/centrifugal/centrifuge"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+)
+
+// AdminAPIPath is the root path of the admin API.
+const AdminAPIPath = "/admin/api/"
+
+// AdminGame is a game with its state and players. When a single game is
+// requested, it comes with the state of the game as seen by each of its
+// players, its scores and its ranking.
+type AdminGame struct {
+	Game    *games.Game            `json:"game"`
+	State   string                 `json:"state"`
+	Players []string               `json:"players"`
+	Views   map[string]interface{} `json:"views,omitempty"`
+	Scores  map[string]int         `json:"scores,omitempty"`
+	Ranking [][]string             `json:"ranking,omitempty"`
+}
+
+// AdminPlayer is a registered player, connected or not.
+type AdminPlayer struct {
+	*players.Player
+	Connected bool `json:"connected"`
+	Bot       bool `json:"bot"`
+}
+
+// KickAdminData identifies the player kicked from a game.
+type KickAdminData struct {
+	IDPlayer string `json:"idPlayer"`
+}
+
+// AnnouncementData is the message broadcast to all clients.
+type AnnouncementData struct {
+	Message string `json:"message"`
+}
+
+// adminAPI returns the handler of the admin API, restricted to the
+// requests providing the admin token as a bearer token.
+func (m *Manager) adminAPI() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			// browsers' event sources cannot set headers
+			token = r.URL.Query().Get("token")
+		}
+		if m.adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(m.adminToken)) != 1 {
+			adminError(w, http.StatusUnauthorized, fmt.Errorf("invalid admin token"))
+			return
+		}
+
+		m.log.Info().Msgf("admin API: %s %s", r.Method, r.URL.Path)
+		ctx, span := tracing.Start(r.Context(), "admin."+r.Method, tracing.Path.String(r.URL.Path))
+		defer span.End()
+
+		path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, AdminAPIPath), "/"), "/")
+		switch {
+		case len(path) == 1 && path[0] == "games" && r.Method == http.MethodGet:
+			m.adminListGames(ctx, w)
+		case len(path) == 2 && path[0] == "games" && r.Method == http.MethodGet:
+			m.adminGame(ctx, w, path[1])
+		case len(path) == 3 && path[0] == "games" && path[2] == "stop" && r.Method == http.MethodPost:
+			m.adminStopGame(ctx, w, path[1])
+		case len(path) == 3 && path[0] == "games" && path[2] == "abort" && r.Method == http.MethodPost:
+			m.adminAbortGame(ctx, w, path[1])
+		case len(path) == 3 && path[0] == "games" && path[2] == "kick" && r.Method == http.MethodPost:
+			m.adminKickPlayer(ctx, w, r, path[1])
+		case len(path) == 1 && path[0] == "players" && r.Method == http.MethodGet:
+			m.adminListPlayers(ctx, w)
+		case len(path) == 2 && path[0] == "players" && r.Method == http.MethodDelete:
+			m.adminUnregisterPlayer(ctx, w, path[1], false)
+		case len(path) == 3 && path[0] == "players" && path[2] == "ban" && r.Method == http.MethodPost:
+			m.adminUnregisterPlayer(ctx, w, path[1], true)
+		case len(path) == 1 && path[0] == "announcements" && r.Method == http.MethodPost:
+			m.adminAnnounce(ctx, w, r)
+		case len(path) == 1 && path[0] == "dashboard" && r.Method == http.MethodGet:
+			m.adminDashboard(w, r)
+		default:
+			adminError(w, http.StatusNotFound, fmt.Errorf("unsupported admin request %s %s", r.Method, r.URL.Path))
+		}
+	})
+}
+
+// adminReply writes the result of an admin request.
+func adminReply(w http.ResponseWriter, result interface{}) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		adminError(w, http.StatusInternalServerError, fmt.Errorf("unable to marshal result: %s", err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"status": %q, "result": %s}`, OK, string(b))
+}
+
+// adminError writes the error of an admin request.
+func adminError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, `{"status": %q, "result": %q}`, KO, err.Error())
+}
+
+// gameState returns the state of a game: lobby, running or over.
+func gameState(game *games.Game) string {
+	switch {
+	case game.IsOver():
+		return "over"
+	case game.IsStarted():
+		return "running"
+	default:
+		return "lobby"
+	}
+}
+
+// adminListGames lists all the live games, private ones included.
+func (m *Manager) adminListGames(ctx context.Context, w http.ResponseWriter) {
+	result := []AdminGame{}
+	for _, game := range m.storage(ctx).ListGames() {
+		result = append(result, AdminGame{Game: game, State: gameState(game), Players: game.Players()})
+	}
+
+	adminReply(w, result)
+}
+
+// adminGame returns a live game with the views of all its players, or the
+// archive of a game no longer live.
+func (m *Manager) adminGame(ctx context.Context, w http.ResponseWriter, id string) {
+	game, err := m.storage(ctx).GameByID(id)
+	if err != nil {
+		archive, archiveErr := m.storage(ctx).ArchivedGame(id)
+		if archiveErr != nil {
+			adminError(w, http.StatusNotFound, fmt.Errorf("unable to retrieve game from its ID %s: %s", id, err.Error()))
+			return
+		}
+		adminReply(w, archive)
+		return
+	}
+
+	views := make(map[string]interface{})
+	for _, pID := range game.Players() {
+		view, err := game.State(pID)
+		if err == nil {
+			views[pID] = view
+		}
+	}
+
+	adminReply(w, AdminGame{
+		Game:    game,
+		State:   gameState(game),
+		Players: game.Players(),
+		Views:   views,
+		Scores:  game.Scores(),
+		Ranking: game.Ranking(),
+	})
+}
+
+// adminStopGame stops a running game, which is archived after the retention time.
+func (m *Manager) adminStopGame(ctx context.Context, w http.ResponseWriter, id string) {
+	err := m.storage(ctx).StopGame(id)
+	if err != nil {
+		adminError(w, http.StatusBadRequest, fmt.Errorf("unable to stop game %s: %s", id, err.Error()))
+		return
+	}
+
+	adminReply(w, struct{}{})
+}
+
+// adminAbortGame removes a game at once: the lobby of a game not started yet
+// is closed, a running game is stopped, and games over are archived.
+func (m *Manager) adminAbortGame(ctx context.Context, w http.ResponseWriter, id string) {
+	game, err := m.storage(ctx).GameByID(id)
+	if err != nil {
+		adminError(w, http.StatusNotFound, fmt.Errorf("unable to retrieve game from its ID %s: %s", id, err.Error()))
+		return
+	}
+
+	if !game.IsStarted() && !game.IsOver() {
+		m.closeLobby(ctx, game, "aborted")
+		adminReply(w, struct{}{})
+		return
+	}
+
+	if !game.IsOver() {
+		err = m.storage(ctx).StopGame(id)
+		if err != nil {
+			adminError(w, http.StatusBadRequest, fmt.Errorf("unable to stop game %s: %s", id, err.Error()))
+			return
+		}
+	}
+	m.archive(ctx, game)
+
+	adminReply(w, struct{}{})
+}
+
+// adminKickPlayer removes a player from the lobby of a game, or makes the
+// player forfeit a running game.
+func (m *Manager) adminKickPlayer(ctx context.Context, w http.ResponseWriter, r *http.Request, id string) {
+	var kickData KickAdminData
+	err := json.NewDecoder(r.Body).Decode(&kickData)
+	if err != nil {
+		adminError(w, http.StatusBadRequest, fmt.Errorf("unable to decode request: %s", err.Error()))
+		return
+	}
+
+	game, err := m.storage(ctx).GameByID(id)
+	if err != nil {
+		adminError(w, http.StatusNotFound, fmt.Errorf("unable to retrieve game from its ID %s: %s", id, err.Error()))
+		return
+	}
+
+	if game.IsStarted() {
+		err = m.leave(ctx, game, kickData.IDPlayer)
+	} else {
+		err = game.RemovePlayer(kickData.IDPlayer)
+		if err == nil {
+			m.publish(ctx, []byte(`{"type": "kick", "emitter": "manager", "id": "`+game.ID.String()+`", "data": "`+m.playerName(ctx, kickData.IDPlayer)+`"}`))
+		}
+	}
+	if err != nil {
+		adminError(w, http.StatusBadRequest, fmt.Errorf("unable to kick player %s: %s", kickData.IDPlayer, err.Error()))
+		return
+	}
+
+	adminReply(w, struct{}{})
+}
+
+// adminListPlayers lists the registered players, with their IDs.
+func (m *Manager) adminListPlayers(ctx context.Context, w http.ResponseWriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := []AdminPlayer{}
+	for _, player := range m.storage(ctx).ListPlayers() {
+		pID := player.ID.String()
+		_, connected := m.playersToClientsMap[pID]
+		_, bot := m.bots[pID]
+		result = append(result, AdminPlayer{Player: player, Connected: connected, Bot: bot})
+	}
+
+	adminReply(w, result)
+}
+
+// adminUnregisterPlayer unregisters a player, who leaves the queue and its
+// games. A banned player is disconnected, and its name is reserved: players
+// register with a name and get a new ID, so banning the ID of an unregistered
+// player would not keep it out, while no one can register the name again.
+func (m *Manager) adminUnregisterPlayer(ctx context.Context, w http.ResponseWriter, id string, ban bool) {
+	player, err := m.storage(ctx).PlayerByID(id)
+	if err != nil {
+		adminError(w, http.StatusNotFound, err)
+		return
+	}
+
+	m.leaveQueue(ctx, id)
+	m.leaveGames(ctx, id)
+
+	err = m.storage(ctx).UnregisterPlayer(id)
+	if err != nil {
+		adminError(w, http.StatusBadRequest, fmt.Errorf("unable to unregister player %s: %s", id, err.Error()))
+		return
+	}
+
+	if ban {
+		m.mu.Lock()
+		m.banned[player.Name] = true
+		client, ok := m.playersToClientsMap[id]
+		m.mu.Unlock()
+
+		if ok {
+			client.Disconnect(centrifuge.DisconnectForceNoReconnect)
+		}
+		m.log.Info().Str(logging.PlayerID, id).Msgf("player %s banned", player.Name)
+	}
+
+	adminReply(w, struct{}{})
+}
+
+// isBanned returns true if the name is reserved by a ban, and players may
+// not register with it.
+func (m *Manager) isBanned(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.banned[name]
+}
+
+// adminAnnounce broadcasts an announcement on the server channel.
+func (m *Manager) adminAnnounce(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var announcement AnnouncementData
+	err := json.NewDecoder(r.Body).Decode(&announcement)
+	if err != nil || announcement.Message == "" {
+		adminError(w, http.StatusBadRequest, fmt.Errorf("expected a message to announce"))
+		return
+	}
+
+	b, err := json.Marshal(struct {
+		Type    string `json:"type"`
+		Emitter string `json:"emitter"`
+		ID      string `json:"id"`
+		Data    string `json:"data"`
+	}{
+		Type:    "announcement",
+		Emitter: "manager",
+		Data:    announcement.Message,
+	})
+	if err != nil {
+		adminError(w, http.StatusInternalServerError, fmt.Errorf("unable to marshal announcement: %s", err.Error()))
+		return
+	}
+	m.publish(ctx, b)
+
+	adminReply(w, struct{}{})
+}
@@ -0,0 +1,145 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games/dice"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+)
+
+type AdminResponse struct {
+	Status string          `json:"status"`
+	Result json.RawMessage `json:"result"`
+}
+
+// admin sends a request to the admin API with a token.
+func admin(t *testing.T, method, path, body, token string) (int, AdminResponse) {
+	var response AdminResponse
+
+	req, err := http.NewRequest(method, "http://localhost:8000"+manager.AdminAPIPath+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error while building request: %s", err.Error())
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error while requesting %s %s: %s", method, path, err.Error())
+	}
+	defer resp.Body.Close()
+
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		t.Fatalf("error while decoding response to %s %s: %s", method, path, err.Error())
+	}
+
+	return resp.StatusCode, response
+}
+
+func TestAdminAPI(t *testing.T) {
+	code, _ := admin(t, http.MethodGet, "games", "", "wrong")
+	if code != http.StatusUnauthorized {
+		t.Errorf("expected admin API to be refused without the admin token, got %d", code)
+	}
+
+	game, ids := newLeaveGame(t, dice.Type, "admined1", "admined2", "admined3")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	code, response := admin(t, http.MethodGet, "games", "", adminToken)
+	if code != http.StatusOK || !strings.Contains(string(response.Result), game.ID.String()) {
+		t.Errorf("expected games to be listed, got %d: %s", code, string(response.Result))
+	}
+
+	// kicked from the lobby
+	code, response = admin(t, http.MethodPost, "games/"+game.ID.String()+"/kick", `{"idPlayer": "`+ids[2]+`"}`, adminToken)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected error while kicking player: %s", string(response.Result))
+	}
+	if len(game.Players()) != 2 {
+		t.Errorf("expected kicked player to leave the game, got %v", game.Players())
+	}
+
+	reply := call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if reply.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", reply.Result)
+	}
+
+	// the game is shown as seen by all its players
+	code, response = admin(t, http.MethodGet, "games/"+game.ID.String(), "", adminToken)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected error while getting game: %s", string(response.Result))
+	}
+	var adminGame struct {
+		Views map[string]json.RawMessage `json:"views"`
+	}
+	err := json.Unmarshal(response.Result, &adminGame)
+	if err != nil || len(adminGame.Views) != 2 {
+		t.Errorf("expected the views of 2 players, got %s", string(response.Result))
+	}
+
+	// aborted games are archived at once
+	code, response = admin(t, http.MethodPost, "games/"+game.ID.String()+"/abort", "", adminToken)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected error while aborting game: %s", string(response.Result))
+	}
+	_, err = store.GameByID(game.ID.String())
+	if err == nil {
+		t.Errorf("expected aborted game to be removed")
+	}
+	archive, err := store.ArchivedGame(game.ID.String())
+	if err != nil || !archive.Aborted {
+		t.Errorf("expected aborted game to be archived, got %+v", archive)
+	}
+
+	code, _ = admin(t, http.MethodGet, "games/"+game.ID.String()+"/unknown", "", adminToken)
+	if code != http.StatusNotFound {
+		t.Errorf("expected unsupported admin request to be refused, got %d", code)
+	}
+}
+
+func TestAdminPlayers(t *testing.T) {
+	ids := registerPlayers(t, "listed", "banned")
+	defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+ids[0]+`", "token": "`+adminToken+`"}`)
+
+	code, response := admin(t, http.MethodGet, "players", "", adminToken)
+	if code != http.StatusOK || !strings.Contains(string(response.Result), ids[0]) {
+		t.Errorf("expected players to be listed with their ID, got %d: %s", code, string(response.Result))
+	}
+
+	code, response = admin(t, http.MethodPost, "players/"+ids[1]+"/ban", "", adminToken)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected error while banning player: %s", string(response.Result))
+	}
+
+	_, err := store.PlayerByID(ids[1])
+	if err == nil {
+		t.Errorf("expected banned player to be unregistered")
+	}
+
+	reply := call(t, rpc(manager.RegisterPlayer), `{"name": "banned"}`)
+	if reply.Status != "ko" {
+		t.Errorf("expected banned player to be refused registration")
+	}
+
+	code, _ = admin(t, http.MethodDelete, "players/"+ids[1], "", adminToken)
+	if code != http.StatusNotFound {
+		t.Errorf("expected unknown player not to be unregistered, got %d", code)
+	}
+}
+
+func TestAdminAnnouncement(t *testing.T) {
+	code, response := admin(t, http.MethodPost, "announcements", `{"message": "server \"restarts\" soon"}`, adminToken)
+	if code != http.StatusOK {
+		t.Errorf("unexpected error while announcing: %s", string(response.Result))
+	}
+
+	code, _ = admin(t, http.MethodPost, "announcements", `{}`, adminToken)
+	if code != http.StatusBadRequest {
+		t.Errorf("expected empty announcement to be refused, got %d", code)
+	}
+}
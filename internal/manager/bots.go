@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goombaio/namegenerator"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/bots"
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+)
+
+type BotData struct {
+	IDGame   uuid.UUID `json:"idGame"`
+	Strategy string    `json:"strategy"`
+	IDPlayer uuid.UUID `json:"idPlayer"`
+	Token    string    `json:"token,omitempty"`
+}
+
+// AddBot registers a bot player and seats it in a game. The bot plays with
+// the requested strategy, greedy by default. Only the host of the game and
+// admins may add bots.
+func (m *Manager) AddBot(ctx context.Context, botData BotData) (*players.Player, error) {
+	game, err := m.hostedGame(ctx, HostData{ID: botData.IDGame, IDPlayer: botData.IDPlayer, Token: botData.Token})
+	if err != nil {
+		return nil, err
+	}
+
+	if botData.Strategy == "" {
+		botData.Strategy = bots.GreedyStrategy
+	}
+
+	seed := time.Now().UTC().UnixNano()
+	strategy, err := bots.NewStrategy(botData.Strategy, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create bot: %s", err.Error())
+	}
+
+	err = bots.Supports(game)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create bot: %s", err.Error())
+	}
+
+	name := "bot-" + botData.Strategy + "-" + namegenerator.NewNameGenerator(seed).Generate()
+	player, err := m.storage(ctx).RegisterPlayer(uuid.Nil.String(), name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to register bot %s: %s", name, err.Error())
+	}
+
+	err = m.storage(ctx).JoinGame(game.ID.String(), player.ID.String())
+	if err != nil {
+		_ = m.storage(ctx).UnregisterPlayer(player.ID.String())
+		return nil, fmt.Errorf("unable to make bot %s join game %s: %s", name, game.ID.String(), err.Error())
+	}
+
+	bot := bots.New(m.root, player.ID.String(), game, strategy)
+	m.mu.Lock()
+	m.bots[player.ID.String()] = bot
+	m.mu.Unlock()
+	_ = game.SetBotControlled(player.ID.String(), true)
+	bot.Start()
+
+	m.publish(ctx, []byte(`{"type": "join", "emitter": "manager", "id": "`+game.ID.String()+`", "data": "`+player.Name+`"}`))
+
+	return player, nil
+}
+
+// releaseBots stops the bots seated in a game and unregisters their players,
+// once the game ended, is archived or its lobby is closed.
+func (m *Manager) releaseBots(ctx context.Context, game *games.Game) {
+	m.mu.Lock()
+	released := []string{}
+	for pID, bot := range m.bots {
+		if bot.Game() == game {
+			bot.Stop()
+			delete(m.bots, pID)
+			released = append(released, pID)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, pID := range released {
+		_ = m.storage(ctx).UnregisterPlayer(pID)
+	}
+}
+
+// isBot returns true if a player is a bot added to a game. Bots are
+// registered players, but they are neither rated nor listed.
+func (m *Manager) isBot(pID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.bots[pID]
+	return ok
+}
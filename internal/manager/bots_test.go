@@ -0,0 +1,119 @@
+package manager_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/centrifugal/centrifuge"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+)
+
+// call runs a manager RPC handler and returns its unmarshaled response.
+func call(t *testing.T, handler func(context.Context, []byte, centrifuge.RPCCallback), data string) Response {
+	var response Response
+
+	replyChan := make(chan []byte, 1)
+	go handler(context.Background(), []byte(data), func(r centrifuge.RPCReply, e error) {
+		if e != nil {
+			t.Errorf("unexpected error: %s", e.Error())
+		}
+		replyChan <- r.Data
+	})
+
+	r := <-replyChan
+	err := json.Unmarshal(r, &response)
+	if err != nil {
+		t.Fatalf("error while unmarshaling response %q: %s", string(r), err.Error())
+	}
+
+	return response
+}
+
+func TestBots(t *testing.T) {
+	response := call(t, rpc(manager.CreateGame), `{"minPlayers": 2, "maxPlayers": 2, "seed": 3}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while creating game: %s", response.Result)
+	}
+
+	var game games.Game
+	err := json.Unmarshal([]byte(response.Result), &game)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	response = call(t, rpc(manager.AddBot), `{"idGame": "`+game.ID.String()+`", "strategy": "fake", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while adding a bot with unknown strategy")
+	}
+
+	// only the host of the game and admins add bots
+	response = call(t, rpc(manager.AddBot), `{"idGame": "`+game.ID.String()+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while adding a bot without being host")
+	}
+
+	ids := []string{}
+	for _, strategy := range []string{"random", ""} {
+		response = call(t, rpc(manager.AddBot), `{"idGame": "`+game.ID.String()+`", "strategy": "`+strategy+`", "token": "`+adminToken+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while adding bot: %s", response.Result)
+		}
+
+		var bot players.Player
+		err = json.Unmarshal([]byte(response.Result), &bot)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+
+		ids = append(ids, bot.ID.String())
+	}
+
+	response = call(t, rpc(manager.AddBot), `{"idGame": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while adding a bot to a full game")
+	}
+
+	// bots are not listed with the players
+	response = call(t, rpc(manager.ListPlayers), `{}`)
+	if strings.Contains(response.Result, `"bot-`) {
+		t.Errorf("expected bots not to be listed, got %s", response.Result)
+	}
+
+	response = call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		response = call(t, rpc(manager.GameLog), `{"id": "`+game.ID.String()+`"}`)
+		log, err := games.ParseLog(response.Result)
+		if err != nil {
+			t.Fatalf("unexpected error parsing game log: %s", err.Error())
+		}
+
+		// the seed is only disclosed once the game is finished
+		if log.Seed != nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("game not finished by bots, log:\n%s", response.Result)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// the bots leave the players registry once the game ended
+	for _, id := range ids {
+		waitFor(t, "bot "+id+" unregistered", func() bool {
+			_, err := store.PlayerByID(id)
+			return err != nil
+		})
+	}
+}
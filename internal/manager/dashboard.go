@@ -0,0 +1,213 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+const (
+	dashboardEvents   = 50
+	dashboardInterval = time.Second
+)
+
+// DashboardEvent is an event published by the manager or a game.
+type DashboardEvent struct {
+	games.Event
+	Time time.Time `json:"time"`
+}
+
+// DashboardSeat is the seat of a player in a game.
+type DashboardSeat struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Bot       bool   `json:"bot"`
+	Connected bool   `json:"connected"`
+	Ready     bool   `json:"ready"`
+	Forfeited bool   `json:"forfeited"`
+}
+
+// DashboardGame is a live game, with its seats and the deadline of its
+// current turn, if any.
+type DashboardGame struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	State        string          `json:"state"`
+	Private      bool            `json:"private"`
+	MaxPlayers   int             `json:"maxPlayers"`
+	Seats        []DashboardSeat `json:"seats"`
+	Current      string          `json:"current"`
+	TurnDeadline *time.Time      `json:"turnDeadline,omitempty"`
+	Scores       map[string]int  `json:"scores"`
+}
+
+// Dashboard is a snapshot of the server state.
+type Dashboard struct {
+	Time    time.Time        `json:"time"`
+	Clients int              `json:"clients"`
+	Players int              `json:"players"`
+	Queued  int              `json:"queued"`
+	Games   []DashboardGame  `json:"games"`
+	Events  []DashboardEvent `json:"events"`
+}
+
+// record keeps an event among the recent events, and sends it to the
+// dashboard watchers. Watchers too slow to receive it miss the event.
+func (m *Manager) record(e games.Event) {
+	event := DashboardEvent{Event: e, Time: time.Now()}
+
+	m.dashMu.Lock()
+	defer m.dashMu.Unlock()
+
+	m.events = append(m.events, event)
+	if len(m.events) > dashboardEvents {
+		m.events = m.events[len(m.events)-dashboardEvents:]
+	}
+
+	for watcher := range m.watchers {
+		select {
+		case watcher <- event:
+		default:
+		}
+	}
+}
+
+// watch registers a dashboard watcher.
+func (m *Manager) watch() chan DashboardEvent {
+	watcher := make(chan DashboardEvent, dashboardEvents)
+
+	m.dashMu.Lock()
+	m.watchers[watcher] = true
+	m.dashMu.Unlock()
+
+	return watcher
+}
+
+// unwatch unregisters a dashboard watcher.
+func (m *Manager) unwatch(watcher chan DashboardEvent) {
+	m.dashMu.Lock()
+	delete(m.watchers, watcher)
+	m.dashMu.Unlock()
+}
+
+// dashboard returns a snapshot of the server state.
+func (m *Manager) dashboard() Dashboard {
+	m.mu.Lock()
+	connected := make(map[string]bool)
+	for pID := range m.playersToClientsMap {
+		connected[pID] = true
+	}
+	queued := len(m.queue)
+	m.mu.Unlock()
+
+	d := Dashboard{
+		Time:    time.Now(),
+		Clients: m.node.Hub().NumClients(),
+		Players: len(m.store.ListPlayers()),
+		Queued:  queued,
+		Games:   []DashboardGame{},
+	}
+
+	for _, game := range m.store.ListGames() {
+		g := DashboardGame{
+			ID:         game.ID.String(),
+			Name:       game.Name,
+			Type:       game.Type,
+			State:      gameState(game),
+			Private:    game.IsPrivate(),
+			MaxPlayers: game.MaxPlayers,
+			Seats:      []DashboardSeat{},
+			Current:    game.CurrentPlayer(),
+			Scores:     game.Scores(),
+		}
+
+		deadline := game.TurnDeadline()
+		if !deadline.IsZero() {
+			g.TurnDeadline = &deadline
+		}
+
+		for _, pID := range game.Players() {
+			seat := DashboardSeat{
+				ID:        pID,
+				Bot:       game.IsBotControlled(pID),
+				Connected: connected[pID],
+				Ready:     game.IsReady(pID),
+				Forfeited: game.HasForfeited(pID),
+			}
+			player, err := m.store.PlayerByID(pID)
+			if err == nil {
+				seat.Name = player.Name
+			}
+			g.Seats = append(g.Seats, seat)
+		}
+
+		d.Games = append(d.Games, g)
+	}
+	sort.Slice(d.Games, func(i, j int) bool { return d.Games[i].Name < d.Games[j].Name })
+
+	m.dashMu.Lock()
+	d.Events = append([]DashboardEvent{}, m.events...)
+	m.dashMu.Unlock()
+
+	return d
+}
+
+// adminDashboard streams the server state as server-sent events: a
+// snapshot every second, and each event as soon as it is published.
+func (m *Manager) adminDashboard(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		adminError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	watcher := m.watch()
+	defer m.unwatch(watcher)
+
+	ticker := time.NewTicker(dashboardInterval)
+	defer ticker.Stop()
+
+	send := func(kind string, v interface{}) bool {
+		b, err := json.Marshal(v)
+		if err != nil {
+			m.log.Error().Msgf("unable to marshal dashboard %s: %s", kind, err.Error())
+			return true
+		}
+		_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", kind, string(b))
+		if err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	if !send("snapshot", m.dashboard()) {
+		return
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-m.done:
+			return
+		case <-ticker.C:
+			if !send("snapshot", m.dashboard()) {
+				return
+			}
+		case e := <-watcher:
+			if !send("event", e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package manager_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games/dice"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+)
+
+func TestDashboard(t *testing.T) {
+	game, ids := newLeaveGame(t, dice.Type, "watched1", "watched2")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// event sources provide the token in the query
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:8000"+manager.AdminAPIPath+"dashboard?token="+adminToken, nil)
+	if err != nil {
+		t.Fatalf("unexpected error while building request: %s", err.Error())
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error while requesting dashboard: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected dashboard to be streamed, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// next returns the next server-sent event of a kind.
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	next := func(kind string) string {
+		event := ""
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: ") && event == kind:
+				return strings.TrimPrefix(line, "data: ")
+			}
+		}
+		t.Fatalf("expected a %s event: %v", kind, scanner.Err())
+		return ""
+	}
+
+	var dashboard manager.Dashboard
+	err = json.Unmarshal([]byte(next("snapshot")), &dashboard)
+	if err != nil {
+		t.Fatalf("error while unmarshaling snapshot: %s", err.Error())
+	}
+
+	found := false
+	for _, g := range dashboard.Games {
+		if g.ID == game.ID.String() {
+			found = true
+			if g.State != "lobby" || len(g.Seats) != 2 || g.Seats[0].Name != "watched1" {
+				t.Errorf("expected lobby with 2 seats, got %+v", g)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected game %s in dashboard, got %+v", game.ID.String(), dashboard.Games)
+	}
+
+	// events are streamed as soon as they are published
+	reply := call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if reply.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", reply.Result)
+	}
+
+	for {
+		var event manager.DashboardEvent
+		err = json.Unmarshal([]byte(next("event")), &event)
+		if err != nil {
+			t.Fatalf("error while unmarshaling event: %s", err.Error())
+		}
+		if event.Type == "start" && event.ID == game.ID.String() {
+			break
+		}
+	}
+}
@@ -1,254 +1,357 @@
 package manager
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
-	"github.com/centrifugal/centrifuge"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
 
 	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
 	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
 )
 
-// ListGames returns all games.
-func (m *Manager) ListGames(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
+type ListGamesData struct {
+	Token string `json:"token,omitempty"`
+}
+
+// ListGames returns all public games, without the IDs of their players.
+// Private games and player IDs are only listed to admins providing the
+// admin token.
+func (m *Manager) ListGames(ctx context.Context, listData ListGamesData) ([]json.RawMessage, error) {
 	var b []byte
 	var err error
 
-	g := m.store.ListGames()
+	admin := m.adminToken != "" && listData.Token == m.adminToken
+
+	g := []json.RawMessage{}
+	for _, game := range m.storage(ctx).ListGames() {
+		switch {
+		case admin:
+			b, err = json.Marshal(game)
+		case !game.IsPrivate():
+			b, err = game.PublicJSON()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal game %s: %s", game.ID.String(), err.Error())
+		}
+		g = append(g, b)
+	}
+
+	return g, nil
+}
+
+type CreateGameData struct {
+	Type       string        `json:"type"`
+	MinPlayers int           `json:"minPlayers"`
+	MaxPlayers int           `json:"maxPlayers"`
+	Seed       *int64        `json:"seed,omitempty"`
+	Options    games.Options `json:"options"`
+	IDPlayer   uuid.UUID     `json:"idPlayer"`
+	Private    bool          `json:"private"`
+}
+
+// UnmarshalJSON unmarshals the data of a game to create, Skyjo games with
+// the standard rules by default.
+func (d *CreateGameData) UnmarshalJSON(data []byte) error {
+	type createGameData CreateGameData
 
-	b, err = json.Marshal(g)
+	game := createGameData{Type: games.Skyjo, Options: games.DefaultOptions()}
+	err := json.Unmarshal(data, &game)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to marshal data %v: %s", g, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return err
 	}
+	*d = CreateGameData(game)
 
-	status = OK
-	msg = string(b)
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+	return nil
 }
 
-// CreateGame instantiates a new game.
-func (m *Manager) CreateGame(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var b []byte
-	var err error
+// CreateGame instantiates a new game of a registered type, Skyjo by default.
+// The seed option is only accepted when the manager allows it. Game options
+// not provided keep the standard rules. The player creating the game, if
+// provided, is its host. Private games are given an invite code, and are
+// not announced to other clients.
+func (m *Manager) CreateGame(ctx context.Context, game CreateGameData) (*games.Game, error) {
+	if !utils.ContainsString(games.Rulesets(), game.Type) {
+		return nil, fmt.Errorf("unknown game type %q", game.Type)
+	}
 
-	var game games.Game
-	err = json.Unmarshal(data, &game)
+	err := game.Options.Validate(game.MinPlayers, game.MaxPlayers)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return nil, fmt.Errorf("invalid game options: %s", err.Error())
 	}
 
-	createdGame, err := m.store.CreateGame(game.MinPlayers, game.MaxPlayers)
-	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to create game %v: %s", game, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+	opts := []games.Option{games.WithType(game.Type), games.WithOptions(game.Options)}
+	if game.Seed != nil {
+		if !m.seedAllowed {
+			return nil, fmt.Errorf("seed option not allowed")
+		}
+		opts = append(opts, games.WithSeed(*game.Seed))
 	}
 
-	b, err = json.Marshal(createdGame)
-	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to marshal data %v: %s", createdGame, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+	if game.IDPlayer != uuid.Nil {
+		_, err = m.storage(ctx).PlayerByID(game.IDPlayer.String())
+		if err != nil {
+			return nil, fmt.Errorf("unknown host %s: %s", game.IDPlayer.String(), err.Error())
+		}
+		opts = append(opts, games.WithHost(game.IDPlayer.String()))
 	}
 
-	status = OK
-	msg = string(b)
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+	if game.Private {
+		code, err := m.newInviteCode(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create private game: %s", err.Error())
+		}
+		opts = append(opts, games.WithInviteCode(code))
+	}
 
-	_, err = m.node.Publish(utils.ServerPublishChannel,
-		[]byte(`{"type": "creation", "emitter": "manager", "id": "`+createdGame.ID.String()+`", "data": ""}`))
+	createdGame, err := m.storage(ctx).CreateGame(game.MinPlayers, game.MaxPlayers, opts...)
 	if err != nil {
-		m.log.Error().Msgf("manager publication error: %s", err.Error())
+		return nil, fmt.Errorf("unable to create game (min %d, max %d): %s", game.MinPlayers, game.MaxPlayers, err.Error())
 	}
+
+	createdGame.OnEvent(m.handleGameEvent(createdGame))
+
+	if !game.Private {
+		m.publish(ctx, []byte(`{"type": "creation", "emitter": "manager", "id": "`+createdGame.ID.String()+`", "data": ""}`))
+	}
+
+	return createdGame, nil
 }
 
 // StartGame starts the game with a given ID.
-func (m *Manager) StartGame(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var err error
-
-	var g games.Game
-	err = json.Unmarshal(data, &g)
+func (m *Manager) StartGame(ctx context.Context, hostData HostData) (Empty, error) {
+	game, err := m.hostedGame(ctx, hostData)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return Empty{}, err
 	}
 
-	game, err := m.store.GameByID(g.ID.String())
+	err = m.startGame(ctx, game)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to retrieve game from its ID: %s", err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return Empty{}, fmt.Errorf("unable to start game %s: %s", game.ID.String(), err.Error())
 	}
 
-	err = m.store.StartGame(game.ID.String())
+	return Empty{}, nil
+}
+
+// startGame starts a game, and publishes a start event.
+func (m *Manager) startGame(ctx context.Context, game *games.Game) error {
+	err := m.storage(ctx).StartGame(game.ID.String())
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to start game %v: %s", game, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return err
 	}
 
 	// publication to all clients who subscribed to a channel
-	_, err = m.node.Publish(utils.ServerPublishChannel,
-		[]byte(`{"type": "start", "emitter": "manager", "id": "`+game.ID.String()+`", "data": ""}`))
-	if err != nil {
-		m.log.Error().Msgf("manager publication error: %s", err.Error())
-	}
+	m.publish(ctx, []byte(`{"type": "start", "emitter": "manager", "id": "`+game.ID.String()+`", "data": ""}`))
 
-	// message to one client
+	// message to one client, bots have no client
 	for _, playerID := range game.Players() {
-		m.playersToClientsMap[playerID].Send([]byte(`{"id": "` + playerID + `", "action": "do something"}`))
+		m.send(ctx, playerID, []byte(`{"id": "`+playerID+`", "action": "do something"}`))
 	}
 
-	status = OK
-	msg = EmptyJSON
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+	return nil
 }
 
-// StopGame stops the game with a given ID.
-func (m *Manager) StopGame(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var err error
+// send sends a message to the client of a player, if connected.
+func (m *Manager) send(ctx context.Context, pID string, message []byte) {
+	m.mu.Lock()
+	client, ok := m.playersToClientsMap[pID]
+	m.mu.Unlock()
+
+	if ok {
+		_, span := tracing.Start(ctx, "send", tracing.PlayerID.String(pID))
+		defer span.End()
+
+		m.metrics.publications.WithLabelValues("client").Inc()
+		err := client.Send(message)
+		if err != nil {
+			m.log.Error().Str(logging.PlayerID, pID).Msgf("error sending message to player: %s", err.Error())
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+}
 
-	var game games.Game
-	err = json.Unmarshal(data, &game)
+// publish sends an event on the server channel, and records it.
+func (m *Manager) publish(ctx context.Context, message []byte) {
+	_, span := tracing.Start(ctx, "publish", tracing.Channel.String(utils.ServerPublishChannel))
+	defer span.End()
+
+	var e games.Event
+	err := json.Unmarshal(message, &e)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+		m.log.Error().Msgf("unable to unmarshal publication %q: %s", string(message), err.Error())
+		span.SetStatus(codes.Error, err.Error())
 		return
 	}
+	span.SetAttributes(tracing.Event.String(e.Type), tracing.GameID.String(e.ID))
+	if e.Player != "" {
+		span.SetAttributes(tracing.PlayerID.String(e.Player))
+	}
 
-	err = m.store.StopGame(game.ID.String())
+	_, err = m.node.Publish(utils.ServerPublishChannel, message)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to stop game %v: %s", game, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+		m.log.Error().Msgf("manager publication error: %s", err.Error())
+		span.SetStatus(codes.Error, err.Error())
 		return
 	}
-
-	status = OK
-	msg = EmptyJSON
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+	m.metrics.publications.WithLabelValues("server").Inc()
+	m.record(e)
 }
 
-// IsGameStarted returns true is game with given ID is started.
-func (m *Manager) IsGameStarted(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var started bool
-	var err error
+// StopGame stops the game with a given ID.
+func (m *Manager) StopGame(ctx context.Context, hostData HostData) (Empty, error) {
+	game, err := m.hostedGame(ctx, hostData)
+	if err != nil {
+		return Empty{}, err
+	}
 
-	var g games.Game
-	err = json.Unmarshal(data, &g)
+	err = m.storage(ctx).StopGame(game.ID.String())
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return Empty{}, fmt.Errorf("unable to stop game %s: %s", game.ID.String(), err.Error())
 	}
 
-	started, err = m.store.IsGameStarted(g.ID.String())
+	return Empty{}, nil
+}
+
+// IsGameStarted returns true is game with given ID is started.
+func (m *Manager) IsGameStarted(ctx context.Context, idData IDData) (bool, error) {
+	started, err := m.storage(ctx).IsGameStarted(idData.ID)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to stop game %s: %s", g.ID.String(), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return false, fmt.Errorf("unable to stop game %s: %s", idData.ID, err.Error())
 	}
 
-	status = OK
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %t}`, status, started))}, nil)
+	return started, nil
 }
 
 type GamePlayerData struct {
-	IDGame   uuid.UUID `json:"idGame"`
-	IDPlayer uuid.UUID `json:"idPlayer"`
+	IDGame   uuid.UUID `json:"idGame" validate:"required"`
+	IDPlayer uuid.UUID `json:"idPlayer" validate:"required"`
+	Token    string    `json:"token,omitempty"`
 }
 
-// JoinGame adds a player to a game.
-func (m *Manager) JoinGame(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var err error
-
-	var joinData GamePlayerData
-	err = json.Unmarshal(data, &joinData)
+// JoinGame adds a player to a public game. Private games are joined
+// with their invite code.
+func (m *Manager) JoinGame(ctx context.Context, joinData GamePlayerData) (Empty, error) {
+	err := m.playerAllowed(ctx, joinData.IDPlayer.String(), joinData.Token)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return Empty{}, err
+	}
+
+	game, err := m.storage(ctx).GameByID(joinData.IDGame.String())
+	if err == nil && game.IsPrivate() {
+		return Empty{}, fmt.Errorf("game %s is private, join it with its invite code", joinData.IDGame.String())
 	}
 
-	err = m.store.JoinGame(joinData.IDGame.String(), joinData.IDPlayer.String())
+	err = m.storage(ctx).JoinGame(joinData.IDGame.String(), joinData.IDPlayer.String())
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to make player %s join game %s: %s", joinData.IDPlayer.String(), joinData.IDGame.String(), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return Empty{}, fmt.Errorf("unable to make player %s join game %s: %s", joinData.IDPlayer.String(), joinData.IDGame.String(), err.Error())
 	}
 
-	player, err := m.store.PlayerByID(joinData.IDPlayer.String())
+	player, err := m.storage(ctx).PlayerByID(joinData.IDPlayer.String())
 	if err != nil {
 		m.log.Error().Msgf("error retrieving player's name: %s", err.Error())
 	} else {
-		_, err = m.node.Publish(utils.ServerPublishChannel,
-			[]byte(`{"type": "join", "emitter": "manager", "id": "`+joinData.IDGame.String()+`", "data": "`+player.Name+`"}`))
-		if err != nil {
-			m.log.Error().Msgf("manager publication error: %s", err.Error())
-		}
+		m.publish(ctx, []byte(`{"type": "join", "emitter": "manager", "id": "`+joinData.IDGame.String()+`", "data": "`+player.Name+`"}`))
 	}
 
-	status = OK
-	msg = EmptyJSON
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+	m.startWhenFull(ctx, game)
+
+	return Empty{}, nil
 }
 
 // PlayerInit starts the game with a given ID.
-func (m *Manager) PlayerInit(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var err error
+func (m *Manager) PlayerInit(ctx context.Context, initData GamePlayerData) (Empty, error) {
+	game, err := m.storage(ctx).GameByID(initData.IDGame.String())
+	if err != nil {
+		return Empty{}, fmt.Errorf("unable to retrieve game from its ID %s: %s", initData.IDGame.String(), err.Error())
+	}
 
-	var initData GamePlayerData
-	err = json.Unmarshal(data, &initData)
+	err = game.PlayerInit(initData.IDPlayer.String())
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return Empty{}, fmt.Errorf("unable to init player %s: %s", initData.IDPlayer.String(), err.Error())
 	}
 
-	game, err := m.store.GameByID(initData.IDGame.String())
+	return Empty{}, nil
+}
+
+type MoveData struct {
+	IDGame   uuid.UUID `json:"idGame"`
+	IDPlayer uuid.UUID `json:"idPlayer"`
+	Move     string    `json:"move"`
+	Token    string    `json:"token,omitempty"`
+}
+
+// PlayMove applies the moves of a player, written in the notation of the game type.
+func (m *Manager) PlayMove(ctx context.Context, moveData MoveData) (Empty, error) {
+	err := m.playerAllowed(ctx, moveData.IDPlayer.String(), moveData.Token)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to retrieve game from its ID %s: %s", initData.IDGame.String(), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return Empty{}, err
 	}
 
-	err = game.PlayerInit(initData.IDPlayer.String())
+	game, err := m.storage(ctx).GameByID(moveData.IDGame.String())
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to init player %s: %s", initData.IDPlayer.String(), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
-		return
+		return Empty{}, fmt.Errorf("unable to retrieve game from its ID %s: %s", moveData.IDGame.String(), err.Error())
+	}
+
+	err = game.Apply(moveData.IDPlayer.String(), moveData.Move)
+	if err != nil {
+		return Empty{}, fmt.Errorf("unable to play move %s: %s", moveData.Move, err.Error())
+	}
+
+	return Empty{}, nil
+}
+
+// GameLog returns the history of the game with a given ID, written in the games notation.
+func (m *Manager) GameLog(ctx context.Context, idData IDData) (string, error) {
+	game, err := m.storage(ctx).GameByID(idData.ID)
+	if err != nil {
+		// games archived once over keep their log
+		archive, archiveErr := m.storage(ctx).ArchivedGame(idData.ID)
+		if archiveErr == nil {
+			return archive.Log, nil
+		}
+
+		return "", fmt.Errorf("unable to retrieve game from its ID: %s", err.Error())
+	}
+
+	// the seed is kept secret until the end of the game, as it reveals the cards
+	log := game.Log()
+	if !game.IsFinished() {
+		log.Seed = nil
+	}
+
+	return log.String(), nil
+}
+
+type GameStateData struct {
+	Type   string         `json:"type"`
+	State  interface{}    `json:"state"`
+	Moves  []string       `json:"moves"`
+	Scores map[string]int `json:"scores"`
+}
+
+// GameState returns the state of a game as seen by a player, with the moves
+// the player may play, whatever the game type.
+func (m *Manager) GameState(ctx context.Context, stateData GamePlayerData) (GameStateData, error) {
+	game, err := m.storage(ctx).GameByID(stateData.IDGame.String())
+	if err != nil {
+		return GameStateData{}, fmt.Errorf("unable to retrieve game from its ID %s: %s", stateData.IDGame.String(), err.Error())
+	}
+
+	state, err := game.State(stateData.IDPlayer.String())
+	if err != nil {
+		return GameStateData{}, fmt.Errorf("unable to get game state of player %s: %s", stateData.IDPlayer.String(), err.Error())
 	}
 
-	status = OK
-	msg = EmptyJSON
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+	return GameStateData{
+		Type:   game.Type,
+		State:  state,
+		Moves:  game.Legal(stateData.IDPlayer.String()),
+		Scores: game.Scores(),
+	}, nil
 }
@@ -1,6 +1,7 @@
 package manager_test
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
 )
 
 func TestGames(t *testing.T) {
@@ -18,7 +20,7 @@ func TestGames(t *testing.T) {
 
 	// first time player registration
 	go func() {
-		mgr.CreateGame([]byte(`{"minPlayers":1, "maxPlayers": 4}`), func(r centrifuge.RPCReply, e error) {
+		rpc(manager.CreateGame)(context.Background(), []byte(`{"minPlayers":2, "maxPlayers": 4}`), func(r centrifuge.RPCReply, e error) {
 			replyChan <- r.Data
 			errChan <- e
 		})
@@ -46,3 +48,18 @@ func TestGames(t *testing.T) {
 		t.Errorf("expected non nil uuid for player name1")
 	}
 }
+
+func TestIsGameStarted(t *testing.T) {
+	game := createGame(t, `{"minPlayers": 2, "maxPlayers": 2}`)
+
+	replyChan := make(chan []byte, 1)
+	rpc(manager.IsGameStarted)(context.Background(), []byte(`{"id": "`+game.ID.String()+`"}`), func(r centrifuge.RPCReply, e error) {
+		replyChan <- r.Data
+	})
+
+	// the result is the JSON bool, not a string
+	r := <-replyChan
+	if string(r) != `{"status": "ok", "result": false}` {
+		t.Errorf("unexpected isGameStarted response %s", string(r))
+	}
+}
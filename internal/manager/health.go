@@ -0,0 +1,42 @@
+package manager
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// healthz tells the process is alive.
+func (m *Manager) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"status": %q}`, OK)
+}
+
+// readyz tells the server is ready to serve clients: the centrifuge node
+// runs, the storage is reachable, and the server is not draining.
+func (m *Manager) readyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := m.ready()
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, `{"status": %q, "result": %q}`, KO, err.Error())
+		return
+	}
+
+	fmt.Fprintf(w, `{"status": %q}`, OK)
+}
+
+// ready returns an error telling why the server is not ready.
+func (m *Manager) ready() error {
+	if m.draining.Load() {
+		return fmt.Errorf("server is draining")
+	}
+	if !m.running.Load() {
+		return fmt.Errorf("centrifuge node is not running")
+	}
+	err := m.store.Ping()
+	if err != nil {
+		return fmt.Errorf("storage is not reachable: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package manager_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	for _, path := range []string{"/healthz", "/readyz"} {
+		resp, err := http.Get("http://localhost:8000" + path)
+		if err != nil {
+			t.Fatalf("unexpected error while probing %s: %s", path, err.Error())
+		}
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("unexpected error while reading %s: %s", path, err.Error())
+		}
+
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(b), `"ok"`) {
+			t.Errorf("expected %s to be ok, got %d: %s", path, resp.StatusCode, string(b))
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+// SuggestMove returns the legal moves of a player in a game, best first, with
+// an estimate of their value. Moves are evaluated with the information visible
+// to the player, and the hints are only sent back to the caller.
+func (m *Manager) SuggestMove(ctx context.Context, hintData GamePlayerData) ([]games.Hint, error) {
+	game, err := m.storage(ctx).GameByID(hintData.IDGame.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve game from its ID %s: %s", hintData.IDGame.String(), err.Error())
+	}
+
+	hints, err := game.Suggest(hintData.IDPlayer.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to suggest a move to player %s: %s", hintData.IDPlayer.String(), err.Error())
+	}
+
+	return hints, nil
+}
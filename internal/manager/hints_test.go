@@ -0,0 +1,43 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+)
+
+func TestSuggestMove(t *testing.T) {
+	response := call(t, rpc(manager.SuggestMove), `{"idGame": "00000000-0000-0000-0000-000000000000", "idPlayer": "00000000-0000-0000-0000-000000000000"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while suggesting a move in an unknown game")
+	}
+
+	for _, noHints := range []bool{false, true} {
+		data, _ := json.Marshal(map[string]interface{}{"minPlayers": 2, "maxPlayers": 2, "options": map[string]bool{"noHints": noHints}})
+		response = call(t, rpc(manager.CreateGame), string(data))
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while creating game: %s", response.Result)
+		}
+
+		var game games.Game
+		err := json.Unmarshal([]byte(response.Result), &game)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+
+		response = call(t, rpc(manager.SuggestMove), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+uuid.NewString()+`"}`)
+		if response.Status != "ko" {
+			t.Errorf("expected error while suggesting a move to an unknown player")
+		}
+
+		disabled := strings.Contains(response.Result, "hints are disabled")
+		if disabled != noHints {
+			t.Errorf("expected hints disabled %v, got %q", noHints, response.Result)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+// WithAdminToken sets the token of the admins, who are allowed to manage
+// any game by providing it in the RPCs restricted to game hosts.
+func WithAdminToken(token string) Option {
+	return func(m *Manager) {
+		m.adminToken = token
+	}
+}
+
+// clientKey is the context key of the ID of the client calling a RPC.
+type clientKey struct{}
+
+// withClient returns a context holding the ID of the client calling a RPC.
+func withClient(ctx context.Context, clientID string) context.Context {
+	return context.WithValue(ctx, clientKey{}, clientID)
+}
+
+// clientOf returns the ID of the client calling a RPC, or an empty string
+// when the RPC is not called by a connected client.
+func clientOf(ctx context.Context) string {
+	clientID, _ := ctx.Value(clientKey{}).(string)
+
+	return clientID
+}
+
+// bindPlayer binds a player to the client registering it, which acts as
+// the player from then on. An error is returned if the player is bound to
+// another client.
+func (m *Manager) bindPlayer(ctx context.Context, pID string) error {
+	clientID := clientOf(ctx)
+	if clientID == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owner, ok := m.playersToOwnersMap[pID]
+	if ok && owner != clientID {
+		return fmt.Errorf("player %s is connected from another client", pID)
+	}
+	m.playersToOwnersMap[pID] = clientID
+
+	return nil
+}
+
+// unbindPlayers unbinds the players of a client, once disconnected.
+func (m *Manager) unbindPlayers(clientID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for pID, owner := range m.playersToOwnersMap {
+		if owner == clientID {
+			delete(m.playersToOwnersMap, pID)
+		}
+	}
+}
+
+// isBound returns true if a player is bound to the client calling a RPC.
+func (m *Manager) isBound(ctx context.Context, pID string) bool {
+	clientID := clientOf(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return clientID != "" && m.playersToOwnersMap[pID] == clientID
+}
+
+// playerAllowed returns an error unless the RPC acting for a player is
+// called from the client bound to the player, or with the admin token.
+func (m *Manager) playerAllowed(ctx context.Context, pID, token string) error {
+	if m.adminToken != "" && token == m.adminToken {
+		return nil
+	}
+
+	if !m.isBound(ctx, pID) {
+		return fmt.Errorf("player %s is not bound to the calling client", pID)
+	}
+
+	return nil
+}
+
+// HostData identifies a game and the player managing it, either its host
+// or an admin providing the admin token. The host must call from the
+// client which registered it.
+type HostData struct {
+	ID       uuid.UUID `json:"id"`
+	IDPlayer uuid.UUID `json:"idPlayer"`
+	Token    string    `json:"token,omitempty"`
+}
+
+// hostedGame returns the game identified by host data, if the player
+// is allowed to manage it and is bound to the calling client.
+func (m *Manager) hostedGame(ctx context.Context, hostData HostData) (*games.Game, error) {
+	game, err := m.storage(ctx).GameByID(hostData.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve game from its ID: %s", err.Error())
+	}
+
+	if m.adminToken != "" && hostData.Token == m.adminToken {
+		return game, nil
+	}
+
+	host := game.Host()
+	if host == "" || host != hostData.IDPlayer.String() || !m.isBound(ctx, host) {
+		return nil, fmt.Errorf("player %s is not allowed to manage game %s", hostData.IDPlayer.String(), game.ID.String())
+	}
+
+	return game, nil
+}
+
+type KickData struct {
+	HostData
+	IDKicked uuid.UUID `json:"idKicked"`
+}
+
+// KickPlayer removes a player from the lobby of a game. Only the host
+// of the game and admins may kick players.
+func (m *Manager) KickPlayer(ctx context.Context, kickData KickData) (Empty, error) {
+	game, err := m.hostedGame(ctx, kickData.HostData)
+	if err != nil {
+		return Empty{}, err
+	}
+
+	err = game.RemovePlayer(kickData.IDKicked.String())
+	if err != nil {
+		return Empty{}, fmt.Errorf("unable to kick player %s: %s", kickData.IDKicked.String(), err.Error())
+	}
+
+	m.publish(ctx, []byte(`{"type": "kick", "emitter": "manager", "id": "`+game.ID.String()+`", "data": "`+m.playerName(ctx, kickData.IDKicked.String())+`"}`))
+
+	return Empty{}, nil
+}
+
+type GameOptionsData struct {
+	HostData
+	Options json.RawMessage `json:"options"`
+}
+
+// SetGameOptions changes the options of a game not started yet. Options not
+// provided are left unchanged. Only the host of the game and admins may
+// change its options.
+func (m *Manager) SetGameOptions(ctx context.Context, optionsData GameOptionsData) (*games.Game, error) {
+	game, err := m.hostedGame(ctx, optionsData.HostData)
+	if err != nil {
+		return nil, err
+	}
+
+	options := game.Settings()
+	err = json.Unmarshal(optionsData.Options, &options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal options %q: %s", string(optionsData.Options), err.Error())
+	}
+
+	err = game.SetOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set game options: %s", err.Error())
+	}
+
+	return game, nil
+}
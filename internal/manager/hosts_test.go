@@ -0,0 +1,213 @@
+package manager_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/centrifugal/centrifuge"
+	gocentrifuge "github.com/centrifugal/centrifuge-go"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// dial connects a client to the test server.
+func dial(t *testing.T) *gocentrifuge.Client {
+	var wg sync.WaitGroup
+
+	log := newLogger()
+	c := utils.NewClient(&log, utils.DefaultWebsocketURL, &wg)
+	wg.Add(1)
+	err := c.Connect()
+	if err != nil {
+		t.Fatalf("connect error: %s", err.Error())
+	}
+	wg.Wait()
+
+	return c
+}
+
+// via returns a handler calling a RPC method through a connected client,
+// which acts as the players it registered.
+func via(c *gocentrifuge.Client, method string) func(context.Context, []byte, centrifuge.RPCCallback) {
+	return func(ctx context.Context, data []byte, cb centrifuge.RPCCallback) {
+		result, err := c.RPC(ctx, method, data)
+		if err != nil {
+			cb(centrifuge.RPCReply{}, err)
+			return
+		}
+		cb(centrifuge.RPCReply{Data: result.Data}, nil)
+	}
+}
+
+func TestHosts(t *testing.T) {
+	ids := []string{}
+	clients := []*gocentrifuge.Client{}
+	for _, name := range []string{"host1", "host2", "host3"} {
+		client := dial(t)
+		defer client.Close()
+
+		response := call(t, via(client, manager.RegisterPlayer), `{"name": "`+name+`"}`)
+		var player players.Player
+		err := json.Unmarshal([]byte(response.Result), &player)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+		ids = append(ids, player.ID.String())
+		clients = append(clients, client)
+	}
+	defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+ids[2]+`", "token": "`+adminToken+`"}`)
+
+	response := call(t, rpc(manager.CreateGame), `{"minPlayers": 2, "maxPlayers": 3, "idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while creating game: %s", response.Result)
+	}
+
+	var created struct {
+		ID   string `json:"id"`
+		Host string `json:"host"`
+	}
+	err := json.Unmarshal([]byte(response.Result), &created)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+	if created.Host != ids[0] {
+		t.Errorf("expected game hosted by %s, got %q", ids[0], created.Host)
+	}
+
+	game, err := store.GameByID(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// players act from the client they are bound to
+	response = call(t, rpc(manager.JoinGame), `{"idGame": "`+created.ID+`", "idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while joining game from another client")
+	}
+	response = call(t, via(clients[1], manager.JoinGame), `{"idGame": "`+created.ID+`", "idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while joining game as another player")
+	}
+
+	for i, id := range ids {
+		response = call(t, via(clients[i], manager.JoinGame), `{"idGame": "`+created.ID+`", "idPlayer": "`+id+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while joining game: %s", response.Result)
+		}
+	}
+
+	response = call(t, rpc(manager.ListGames), `{}`)
+	var listed []map[string]interface{}
+	err = json.Unmarshal([]byte(response.Result), &listed)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+	// player IDs are only listed to admins
+	for _, g := range listed {
+		if g["id"] == created.ID && (g["host"] != "" || len(g["ready"].([]interface{})) != 0) {
+			t.Errorf("expected player IDs to be blanked in list %s", response.Result)
+		}
+	}
+
+	response = call(t, rpc(manager.ListGames), `{"token": "`+adminToken+`"}`)
+	err = json.Unmarshal([]byte(response.Result), &listed)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+	found := false
+	for _, g := range listed {
+		if g["id"] == created.ID {
+			found = g["host"] == ids[0]
+		}
+	}
+	if !found {
+		t.Errorf("expected game hosted by %s in admin list %s", ids[0], response.Result)
+	}
+
+	// a player is bound to the client which registered it
+	response = call(t, via(clients[2], manager.RegisterPlayer), `{"id": "`+ids[0]+`", "name": "host1"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while registering a player connected from another client")
+	}
+
+	response = call(t, via(clients[1], manager.KickPlayer), `{"id": "`+created.ID+`", "idPlayer": "`+ids[1]+`", "idKicked": "`+ids[2]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while kicking a player without being host")
+	}
+
+	response = call(t, via(clients[1], manager.KickPlayer), `{"id": "`+created.ID+`", "idPlayer": "`+ids[0]+`", "idKicked": "`+ids[2]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while kicking a player as the host from another client")
+	}
+
+	response = call(t, rpc(manager.KickPlayer), `{"id": "`+created.ID+`", "idPlayer": "`+ids[0]+`", "idKicked": "`+ids[2]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while kicking a player as the host without client")
+	}
+
+	response = call(t, via(clients[0], manager.KickPlayer), `{"id": "`+created.ID+`", "idPlayer": "`+ids[0]+`", "idKicked": "`+ids[2]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while kicking player: %s", response.Result)
+	}
+	if len(game.Players()) != 2 {
+		t.Errorf("expected 2 players left, got %v", game.Players())
+	}
+
+	response = call(t, via(clients[1], manager.SetGameOptions), `{"id": "`+created.ID+`", "idPlayer": "`+ids[1]+`", "options": {"scoreLimit": 50}}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while setting options without being host")
+	}
+
+	response = call(t, via(clients[0], manager.SetGameOptions), `{"id": "`+created.ID+`", "idPlayer": "`+ids[0]+`", "options": {"scoreLimit": -1}}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while setting invalid options")
+	}
+
+	response = call(t, via(clients[0], manager.SetGameOptions), `{"id": "`+created.ID+`", "idPlayer": "`+ids[0]+`", "options": {"scoreLimit": 50}}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while setting options: %s", response.Result)
+	}
+	if game.Options.ScoreLimit != 50 || !game.Options.ColumnClears {
+		t.Errorf("expected score limit 50 with other options unchanged, got %+v", game.Options)
+	}
+
+	// the host leaves, the other player becomes host
+	response = call(t, via(clients[1], manager.UnregisterPlayer), `{"id": "`+ids[0]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while unregistering another player")
+	}
+	call(t, via(clients[0], manager.UnregisterPlayer), `{"id": "`+ids[0]+`"}`)
+	defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+ids[1]+`", "token": "`+adminToken+`"}`)
+	if game.Host() != ids[1] {
+		t.Errorf("expected host handed over to %s, got %q", ids[1], game.Host())
+	}
+
+	response = call(t, via(clients[2], manager.JoinGame), `{"idGame": "`+created.ID+`", "idPlayer": "`+ids[2]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while joining game: %s", response.Result)
+	}
+
+	response = call(t, via(clients[0], manager.StartGame), `{"id": "`+created.ID+`", "idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while starting game without being host")
+	}
+
+	response = call(t, via(clients[1], manager.StartGame), `{"id": "`+created.ID+`", "idPlayer": "`+ids[1]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	response = call(t, rpc(manager.StopGame), `{"id": "`+created.ID+`", "token": "wrong"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while stopping game with a wrong admin token")
+	}
+
+	response = call(t, rpc(manager.StopGame), `{"id": "`+created.ID+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Errorf("unexpected error while stopping game as admin: %s", response.Result)
+	}
+}
@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+)
+
+// maxInviteCodeAttempts bounds the generation of an invite code not used
+// by another game.
+const maxInviteCodeAttempts int = 10
+
+// newInviteCode returns an invite code not used by any game.
+func (m *Manager) newInviteCode(ctx context.Context) (string, error) {
+	for i := 0; i < maxInviteCodeAttempts; i++ {
+		code, err := games.NewInviteCode()
+		if err != nil {
+			return "", err
+		}
+
+		_, err = m.storage(ctx).GameByCode(code)
+		if err != nil {
+			return code, nil
+		}
+	}
+
+	return "", fmt.Errorf("no invite code available after %d attempts", maxInviteCodeAttempts)
+}
+
+type CodePlayerData struct {
+	Code     string    `json:"code"`
+	IDPlayer uuid.UUID `json:"idPlayer"`
+}
+
+// JoinGameByCode adds a player to a private game from its invite code,
+// and returns the game.
+func (m *Manager) JoinGameByCode(ctx context.Context, joinData CodePlayerData) (*games.Game, error) {
+	game, err := m.storage(ctx).GameByCode(games.NormalizeInviteCode(joinData.Code))
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve game from its invite code: %s", err.Error())
+	}
+
+	err = m.storage(ctx).JoinGame(game.ID.String(), joinData.IDPlayer.String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to make player %s join game %s: %s", joinData.IDPlayer.String(), game.ID.String(), err.Error())
+	}
+
+	player, err := m.storage(ctx).PlayerByID(joinData.IDPlayer.String())
+	if err != nil {
+		m.log.Error().Msgf("error retrieving player's name: %s", err.Error())
+	} else {
+		m.publish(ctx, []byte(`{"type": "join", "emitter": "manager", "id": "`+game.ID.String()+`", "data": "`+player.Name+`"}`))
+	}
+
+	m.startWhenFull(ctx, game)
+
+	return game, nil
+}
+
+// RotateInviteCode replaces the invite code of a private game, and returns
+// the game with its new code. Only the host of the game and admins may
+// rotate its code.
+func (m *Manager) RotateInviteCode(ctx context.Context, hostData HostData) (*games.Game, error) {
+	game, err := m.hostedGame(ctx, hostData)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := m.newInviteCode(ctx)
+	if err == nil {
+		err = game.SetInviteCode(code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to rotate invite code: %s", err.Error())
+	}
+
+	return game, nil
+}
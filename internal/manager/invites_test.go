@@ -0,0 +1,135 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+type privateGame struct {
+	ID         string `json:"id"`
+	Private    bool   `json:"private"`
+	InviteCode string `json:"inviteCode"`
+}
+
+// listed returns true if a game is listed by listGames called with given data.
+func listed(t *testing.T, id, data string) bool {
+	response := call(t, rpc(manager.ListGames), data)
+	var list []privateGame
+	err := json.Unmarshal([]byte(response.Result), &list)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	for _, g := range list {
+		if g.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestPrivateGame(t *testing.T) {
+	host := dial(t)
+	defer host.Close()
+
+	ids := []string{}
+	for i, name := range []string{"private1", "private2", "private3"} {
+		register := rpc(manager.RegisterPlayer)
+		if i == 0 {
+			register = via(host, manager.RegisterPlayer)
+		}
+		response := call(t, register, `{"name": "`+name+`"}`)
+		var player players.Player
+		err := json.Unmarshal([]byte(response.Result), &player)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+		ids = append(ids, player.ID.String())
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+player.ID.String()+`", "token": "`+adminToken+`"}`)
+	}
+
+	response := call(t, rpc(manager.CreateGame), `{"minPlayers": 2, "maxPlayers": 3, "private": true, "idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while creating game: %s", response.Result)
+	}
+
+	var game privateGame
+	err := json.Unmarshal([]byte(response.Result), &game)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+	if !game.Private || game.InviteCode == "" {
+		t.Fatalf("expected private game with an invite code, got %s", response.Result)
+	}
+
+	if listed(t, game.ID, `{}`) {
+		t.Errorf("expected private game to be hidden from listGames")
+	}
+	if !listed(t, game.ID, `{"token": "`+adminToken+`"}`) {
+		t.Errorf("expected private game to be listed to admins")
+	}
+
+	response = call(t, rpc(manager.JoinGame), `{"idGame": "`+game.ID+`", "idPlayer": "`+ids[1]+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while joining a private game from its ID")
+	}
+
+	// codes typed by players are normalized
+	code := strings.ToLower(game.InviteCode[:3]) + " " + game.InviteCode[3:]
+	response = call(t, rpc(manager.JoinGameByCode), `{"code": "`+code+`", "idPlayer": "`+ids[1]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while joining game by code: %s", response.Result)
+	}
+
+	var joined privateGame
+	err = json.Unmarshal([]byte(response.Result), &joined)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+	if joined.ID != game.ID {
+		t.Errorf("expected to join game %s, got %s", game.ID, joined.ID)
+	}
+
+	response = call(t, rpc(manager.RotateInviteCode), `{"id": "`+game.ID+`", "idPlayer": "`+ids[1]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while rotating invite code without being host")
+	}
+
+	response = call(t, via(host, manager.RotateInviteCode), `{"id": "`+game.ID+`", "idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while rotating invite code: %s", response.Result)
+	}
+
+	var rotated privateGame
+	err = json.Unmarshal([]byte(response.Result), &rotated)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+	if rotated.InviteCode == "" || rotated.InviteCode == game.InviteCode {
+		t.Errorf("expected a new invite code, got %q", rotated.InviteCode)
+	}
+
+	response = call(t, rpc(manager.JoinGameByCode), `{"code": "`+game.InviteCode+`", "idPlayer": "`+ids[2]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while joining game with a rotated invite code")
+	}
+
+	response = call(t, rpc(manager.JoinGameByCode), `{"code": "`+rotated.InviteCode+`", "idPlayer": "`+ids[2]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while joining game by code: %s", response.Result)
+	}
+
+	g, err := store.GameByID(game.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !utils.ContainsString(g.Players(), ids[2]) {
+		t.Errorf("expected player %s to join the game, got %v", ids[2], g.Players())
+	}
+}
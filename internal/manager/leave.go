@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// LeaveGame removes a player from a game. Leaving a game not started yet
+// frees the seat, leaving a running game is a forfeit.
+func (m *Manager) LeaveGame(ctx context.Context, leaveData GamePlayerData) (Empty, error) {
+	err := m.playerAllowed(ctx, leaveData.IDPlayer.String(), leaveData.Token)
+	if err != nil {
+		return Empty{}, err
+	}
+
+	game, err := m.storage(ctx).GameByID(leaveData.IDGame.String())
+	if err != nil {
+		return Empty{}, fmt.Errorf("unable to retrieve game from its ID %s: %s", leaveData.IDGame.String(), err.Error())
+	}
+
+	err = m.leave(ctx, game, leaveData.IDPlayer.String())
+	if err != nil {
+		return Empty{}, fmt.Errorf("unable to make player %s leave game %s: %s", leaveData.IDPlayer.String(), game.ID.String(), err.Error())
+	}
+
+	return Empty{}, nil
+}
+
+// leave removes a player from a game, and publishes a leave event. The seat
+// is freed in the lobby. In a running game, the player forfeits: when bot
+// takeover is enabled, the seat is handed to a bot for good, otherwise the
+// turns of the player are skipped. Leaving a finished game only hands its
+// hosting over.
+func (m *Manager) leave(ctx context.Context, game *games.Game, pID string) error {
+	if !utils.ContainsString(game.Players(), pID) {
+		return fmt.Errorf("player %s did not join the game", pID)
+	}
+
+	switch {
+	case !game.IsStarted():
+		err := game.RemovePlayer(pID)
+		if err != nil {
+			return err
+		}
+	case !game.IsFinished():
+		err := game.Forfeit(pID)
+		if err != nil {
+			return err
+		}
+
+		if m.takeoverEnabled {
+			m.takeover(game, pID)
+		}
+	default:
+		game.ReleaseHost(pID)
+		return nil
+	}
+
+	m.log.Info().Str(logging.GameID, game.ID.String()).Str(logging.PlayerID, pID).Msg("player left the game")
+	m.publish(ctx, []byte(`{"type": "leave", "emitter": "manager", "id": "`+game.ID.String()+`", "data": "`+m.playerName(ctx, pID)+`"}`))
+
+	return nil
+}
+
+// leaveGames removes a player from all the games it joined, and hands over
+// the hosting of the games it hosts.
+func (m *Manager) leaveGames(ctx context.Context, pID string) {
+	for _, game := range m.storage(ctx).ListGames() {
+		if !utils.ContainsString(game.Players(), pID) {
+			game.ReleaseHost(pID)
+			continue
+		}
+
+		err := m.leave(ctx, game, pID)
+		if err != nil {
+			m.log.Error().Str(logging.GameID, game.ID.String()).Str(logging.PlayerID, pID).Msgf("error removing player: %s", err.Error())
+		}
+	}
+}
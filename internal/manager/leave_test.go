@@ -0,0 +1,134 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/games/dice"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// newLeaveGame creates a game of a given type joined by new players.
+func newLeaveGame(t *testing.T, kind string, names ...string) (*games.Game, []string) {
+	response := call(t, rpc(manager.CreateGame), `{"type": "`+kind+`", "minPlayers": 2, "maxPlayers": 3}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while creating game: %s", response.Result)
+	}
+
+	var g games.Game
+	err := json.Unmarshal([]byte(response.Result), &g)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	game, err := store.GameByID(g.ID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	ids := []string{}
+	for _, name := range names {
+		response = call(t, rpc(manager.RegisterPlayer), `{"name": "`+name+`"}`)
+		var player players.Player
+		err = json.Unmarshal([]byte(response.Result), &player)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+
+		ids = append(ids, player.ID.String())
+		response = call(t, rpc(manager.JoinGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+player.ID.String()+`", "token": "`+adminToken+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while joining game: %s", response.Result)
+		}
+	}
+
+	return game, ids
+}
+
+func TestLeaveGame(t *testing.T) {
+	game, ids := newLeaveGame(t, games.Skyjo, "leave1", "leave2", "leave3")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	// leaving the lobby frees the seat
+	response := call(t, rpc(manager.LeaveGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[2]+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while leaving game: %s", response.Result)
+	}
+	if utils.ContainsString(game.Players(), ids[2]) {
+		t.Errorf("expected player %s to leave the lobby, got %v", ids[2], game.Players())
+	}
+
+	response = call(t, rpc(manager.LeaveGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[2]+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while leaving a game twice")
+	}
+
+	response = call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	// the error only tells the game ID, not the cards of the game
+	response = call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" || response.Result != "unable to start game "+game.ID.String()+": error starting game: ["+game.Name+"] game already started" {
+		t.Errorf("expected error while starting a game twice, got %+v", response)
+	}
+
+	// leaving a running game is a forfeit, the seat is handed to a bot for good
+	response = call(t, rpc(manager.LeaveGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[0]+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while leaving game: %s", response.Result)
+	}
+	if !game.HasForfeited(ids[0]) || !game.IsBotControlled(ids[0]) {
+		t.Errorf("expected seat of player %s to be forfeited and played by a bot", ids[0])
+	}
+}
+
+func TestLeaveDiceGame(t *testing.T) {
+	game, ids := newLeaveGame(t, dice.Type, "dice3", "dice4", "dice5")
+	for _, id := range ids[1:] {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	response := call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	// unregistering a player forfeits its running games, its turns are skipped
+	call(t, rpc(manager.UnregisterPlayer), `{"id": "`+ids[0]+`", "token": "`+adminToken+`"}`)
+	if !game.HasForfeited(ids[0]) || game.IsBotControlled(ids[0]) {
+		t.Errorf("expected seat of player %s to be forfeited", ids[0])
+	}
+
+	response = call(t, rpc(manager.GameState), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`"}`)
+	var state struct {
+		State dice.State `json:"state"`
+	}
+	err := json.Unmarshal([]byte(response.Result), &state)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+	if state.State.Current != ids[1] {
+		t.Errorf("expected turn of player %s, got %s", ids[1], state.State.Current)
+	}
+
+	// the last player left wins
+	response = call(t, rpc(manager.LeaveGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[2]+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while leaving game: %s", response.Result)
+	}
+	if !game.IsFinished() {
+		t.Errorf("expected game to end when a single player is left")
+	}
+
+	response = call(t, rpc(manager.PlayMove), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`", "move": "roll", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while playing a finished game")
+	}
+}
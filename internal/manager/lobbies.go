@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+)
+
+const (
+	defaultLobbyExpiry = 30 * time.Minute
+	defaultLobbySweep  = 10 * time.Second
+)
+
+// WithLobbyExpiry sets how long the lobby of a game not started yet stays
+// open without activity, unless the game options tell otherwise, and how
+// often lobbies are checked. Lobbies never expire by default if expiry is 0.
+func WithLobbyExpiry(expiry, interval time.Duration) Option {
+	return func(m *Manager) {
+		m.lobbyExpiry = expiry
+		m.lobbySweep = interval
+	}
+}
+
+// sweepLobbies closes the expired lobbies until the manager shuts down.
+func (m *Manager) sweepLobbies() {
+	ticker := time.NewTicker(m.lobbySweep)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			for _, game := range m.store.ListGames() {
+				if m.expired(game) {
+					ctx, span := tracing.Start(context.Background(), "lobby.expire", tracing.GameID.String(game.ID.String()))
+					m.closeLobby(ctx, game, "expired")
+					span.End()
+				}
+			}
+		}
+	}
+}
+
+// expired returns true if the lobby of a game not started yet had no
+// activity for longer than its lobby timeout.
+func (m *Manager) expired(game *games.Game) bool {
+	if game.IsStarted() {
+		return false
+	}
+
+	expiry := m.lobbyExpiry
+	if timeout := game.Settings().LobbyTimeout; timeout > 0 {
+		expiry = time.Duration(timeout) * time.Second
+	}
+
+	return expiry > 0 && time.Since(game.LastActivity()) > expiry
+}
+
+// closeLobby removes a game not started yet, cancels its pending match,
+// stops its bots, disconnects it, and publishes a closed event with the reason in data.
+func (m *Manager) closeLobby(ctx context.Context, game *games.Game, reason string) {
+	gameID := game.ID.String()
+
+	// the game of a pending match is dropped with the match
+	if !m.cancelMatch(ctx, gameID, "") {
+		err := m.storage(ctx).RemoveGame(gameID)
+		if err != nil {
+			m.log.Error().Str(logging.GameID, game.ID.String()).Msgf("unable to remove game: %s", err.Error())
+			return
+		}
+		m.releaseBots(ctx, game)
+		game.Close()
+	}
+
+	m.log.Info().Str(logging.GameID, game.ID.String()).Msgf("lobby closed: %s", reason)
+	m.publish(ctx, []byte(`{"type": "closed", "emitter": "manager", "id": "`+gameID+`", "data": "`+reason+`"}`))
+}
+
+// startWhenFull starts a game created with the start when full option once
+// its last seat is taken.
+func (m *Manager) startWhenFull(ctx context.Context, game *games.Game) {
+	if !game.Settings().StartWhenFull || len(game.Players()) < game.MaxPlayers {
+		return
+	}
+
+	err := m.startGame(ctx, game)
+	if err != nil {
+		m.log.Error().Str(logging.GameID, game.ID.String()).Msgf("unable to start full game: %s", err.Error())
+	}
+}
@@ -0,0 +1,109 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+)
+
+// createGame creates a game with given data, and returns it.
+func createGame(t *testing.T, data string) *games.Game {
+	response := call(t, rpc(manager.CreateGame), data)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while creating game: %s", response.Result)
+	}
+
+	var g games.Game
+	err := json.Unmarshal([]byte(response.Result), &g)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	game, err := store.GameByID(g.ID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return game
+}
+
+func TestStartWhenFull(t *testing.T) {
+	game := createGame(t, `{"minPlayers": 2, "maxPlayers": 3, "options": {"startWhenFull": true}}`)
+	ids := registerPlayers(t, "full1", "full2", "full3")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	for i, id := range ids {
+		if game.IsStarted() {
+			t.Fatalf("expected game not to start with %d players", i)
+		}
+
+		response := call(t, rpc(manager.JoinGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+id+`", "token": "`+adminToken+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while joining game: %s", response.Result)
+		}
+	}
+
+	if !game.IsStarted() {
+		t.Errorf("expected game to start once full")
+	}
+}
+
+func TestLobbyExpiry(t *testing.T) {
+	game := createGame(t, `{"minPlayers": 2, "maxPlayers": 3, "options": {"lobbyTimeout": 1}}`)
+	started := createGame(t, `{"minPlayers": 2, "maxPlayers": 3, "options": {"lobbyTimeout": 1}}`)
+	for _, name := range []string{"expiry1", "expiry2"} {
+		response := call(t, rpc(manager.RegisterPlayer), `{"name": "`+name+`"}`)
+		var player players.Player
+		err := json.Unmarshal([]byte(response.Result), &player)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+player.ID.String()+`", "token": "`+adminToken+`"}`)
+
+		response = call(t, rpc(manager.JoinGame), `{"idGame": "`+started.ID.String()+`", "idPlayer": "`+player.ID.String()+`", "token": "`+adminToken+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while joining game: %s", response.Result)
+		}
+	}
+
+	response := call(t, rpc(manager.AddBot), `{"idGame": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while adding bot: %s", response.Result)
+	}
+	var bot players.Player
+	err := json.Unmarshal([]byte(response.Result), &bot)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	response = call(t, rpc(manager.StartGame), `{"id": "`+started.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	waitFor(t, "lobby closed", func() bool {
+		_, err := store.GameByID(game.ID.String())
+		return err != nil
+	})
+
+	_, err = store.GameByID(started.ID.String())
+	if err != nil {
+		t.Errorf("expected started game not to expire: %s", err.Error())
+	}
+
+	// the bots of the lobby are stopped and unregistered
+	waitFor(t, "bot unregistered", func() bool {
+		_, err := store.PlayerByID(bot.ID.String())
+		return err != nil
+	})
+
+	response = call(t, rpc(manager.JoinGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+started.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while joining a closed lobby")
+	}
+}
@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// WithLogStream publishes the entries of a log stream on the admin logs
+// channel, which only the admins may subscribe to.
+func WithLogStream(s *logging.Stream) Option {
+	return func(m *Manager) {
+		m.logStream = s
+	}
+}
+
+// LogsSubscribeData is the data of a subscription to the admin logs channel.
+type LogsSubscribeData struct {
+	Token string `json:"token"`
+}
+
+// logsAllowed tells whether the data of a subscription to the admin logs
+// channel provides the admin token.
+func (m *Manager) logsAllowed(data []byte) bool {
+	var logsData LogsSubscribeData
+	err := json.Unmarshal(data, &logsData)
+	if err != nil {
+		return false
+	}
+
+	return m.adminToken != "" && subtle.ConstantTimeCompare([]byte(logsData.Token), []byte(m.adminToken)) == 1
+}
+
+// streamLogs publishes the entries of the log stream until the manager is
+// shut down.
+func (m *Manager) streamLogs() {
+	m.logStream.Run(m.done, func(data []byte) error {
+		_, err := m.node.Publish(utils.AdminLogsChannel, data)
+		if err == nil {
+			m.metrics.publications.WithLabelValues("logs").Inc()
+		}
+		return err
+	})
+}
@@ -0,0 +1,104 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	centrifuge "github.com/centrifugal/centrifuge-go"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// tailLogs subscribes to the admin logs channel with a token, and returns the
+// channel of the entries received, which is closed if the subscription is
+// refused.
+func tailLogs(t *testing.T, token string) (*centrifuge.Client, chan []byte) {
+	var wg sync.WaitGroup
+
+	log := newLogger()
+	c := utils.NewClient(&log, utils.DefaultWebsocketURL, &wg)
+	wg.Add(1)
+	err := c.Connect()
+	if err != nil {
+		t.Fatalf("connect error: %s", err.Error())
+	}
+	wg.Wait()
+
+	entries := make(chan []byte, 100)
+	subscribed := make(chan bool, 1)
+	subscription, err := c.NewSubscription(utils.AdminLogsChannel, centrifuge.SubscriptionConfig{
+		Data: []byte(`{"token": "` + token + `"}`),
+	})
+	if err != nil {
+		t.Fatalf("subscription error: %s", err.Error())
+	}
+	subscription.OnSubscribed(func(e centrifuge.SubscribedEvent) {
+		subscribed <- true
+	})
+	subscription.OnUnsubscribed(func(e centrifuge.UnsubscribedEvent) {
+		subscribed <- false
+	})
+	subscription.OnPublication(func(e centrifuge.PublicationEvent) {
+		select {
+		case entries <- e.Data:
+		default:
+		}
+	})
+
+	err = subscription.Subscribe()
+	if err != nil {
+		t.Fatalf("subscribe error: %s", err.Error())
+	}
+
+	select {
+	case ok := <-subscribed:
+		if !ok {
+			close(entries)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout subscribing to %s", utils.AdminLogsChannel)
+	}
+
+	return c, entries
+}
+
+func TestLogStream(t *testing.T) {
+	c, entries := tailLogs(t, "wrong")
+	c.Close()
+	if _, ok := <-entries; ok {
+		t.Fatalf("expected subscription to the logs to be refused without the admin token")
+	}
+
+	c, entries = tailLogs(t, adminToken)
+	defer c.Close()
+
+	code, _ := admin(t, http.MethodGet, "games", "", adminToken)
+	if code != http.StatusOK {
+		t.Fatalf("expected games to be listed, got %d", code)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case data := <-entries:
+			var entry map[string]interface{}
+			err := json.Unmarshal(data, &entry)
+			if err != nil {
+				t.Fatalf("expected JSON log entry, got %q", string(data))
+			}
+			if entry[logging.ComponentField] != "manager" {
+				t.Errorf("expected only manager logs to be streamed, got %v", entry)
+			}
+			if strings.Contains(entry["message"].(string), "admin API: GET") {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for the admin API log entry")
+		}
+	}
+}
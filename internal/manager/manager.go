@@ -4,11 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
-	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/bots"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 	"github.com/jtbonhomme/gameserver-websocket/internal/storage"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/rs/zerolog"
@@ -16,19 +21,67 @@ import (
 
 const (
 	defaultShutdownTimeout = 3 * time.Second
+	defaultAddr            = ":8000"
 )
 
 type Manager struct {
 	log                 *zerolog.Logger
+	root                *zerolog.Logger // root is the logger the loggers of bots derive from.
 	err                 chan error
 	node                *centrifuge.Node
 	shutdownTimeout     time.Duration
 	store               storage.Storage
 	playersToClientsMap map[string]*centrifuge.Client
+	playersToOwnersMap  map[string]string // playersToOwnersMap binds players to the ID of the client which registered them.
+	seedAllowed         bool
+	mu                  sync.Mutex
+	bots                map[string]*bots.Bot
+	takeoverEnabled     bool
+	takeoverGrace       time.Duration
+	takeoverTimers      map[string]*time.Timer
+	takeovers           map[string][]takeover
+	adminToken          string
+	banned              map[string]bool // names reserved by a ban
+	matchInterval       time.Duration
+	confirmTimeout      time.Duration
+	ratingTolerance     float64
+	ratingWidening      float64
+	readyCountdown      time.Duration
+	countdowns          map[string]bool
+	lobbyExpiry         time.Duration
+	lobbySweep          time.Duration
+	gameRetention       time.Duration
+	reapInterval        time.Duration
+	queue               []ticket
+	matches             map[string]*match
+	done                chan struct{}
+	metrics             *metrics
+	server              *http.Server
+	running             atomic.Bool // running is set while the centrifuge node runs.
+	draining            atomic.Bool // draining is set once the shutdown started.
+	dashMu              sync.Mutex  // dashMu guards the recent events and the dashboard watchers.
+	events              []DashboardEvent
+	watchers            map[chan DashboardEvent]bool
+	logStream           *logging.Stream
+	rpcMu               sync.RWMutex // rpcMu guards the handlers of the RPC methods.
+	handlers            map[string]Handler
+	fallback            Handler // fallback replies to the calls of unsupported methods.
+	middleware          []Middleware
+}
+
+// Option configures a manager.
+type Option func(m *Manager)
+
+// WithSeedAllowed allows clients to provide the seed of the games they create,
+// which makes the cards dealt predictable. It is meant for tests and admins only.
+func WithSeedAllowed() Option {
+	return func(m *Manager) {
+		m.seedAllowed = true
+	}
 }
 
 type IDData struct {
-	ID string `json:"id"`
+	ID string `json:"id" validate:"required"`
 }
 
 func auth(h http.Handler) http.Handler {
@@ -49,21 +102,44 @@ func auth(h http.Handler) http.Handler {
 }
 
 // New creates a new Manager instance.
-func New(l *zerolog.Logger, s storage.Storage) *Manager {
-	output := zerolog.ConsoleWriter{
-		Out:           os.Stderr,
-		TimeFormat:    time.RFC3339,
-		FormatMessage: func(i interface{}) string { return fmt.Sprintf("[manager] %s", i) },
-	}
-	logger := l.Output(output)
+func New(l *zerolog.Logger, s storage.Storage, opts ...Option) *Manager {
+	logger := logging.Component(l, "manager")
 
-	return &Manager{
+	m := &Manager{
 		log:                 &logger,
+		root:                l,
 		err:                 make(chan error),
 		shutdownTimeout:     defaultShutdownTimeout,
 		store:               s,
 		playersToClientsMap: make(map[string]*centrifuge.Client),
+		playersToOwnersMap:  make(map[string]string),
+		bots:                make(map[string]*bots.Bot),
+		banned:              make(map[string]bool),
+		takeoverTimers:      make(map[string]*time.Timer),
+		takeovers:           make(map[string][]takeover),
+		matchInterval:       defaultMatchInterval,
+		confirmTimeout:      defaultConfirmTimeout,
+		ratingTolerance:     defaultRatingTolerance,
+		ratingWidening:      defaultRatingWidening,
+		readyCountdown:      defaultReadyCountdown,
+		countdowns:          make(map[string]bool),
+		lobbyExpiry:         defaultLobbyExpiry,
+		lobbySweep:          defaultLobbySweep,
+		gameRetention:       defaultGameRetention,
+		reapInterval:        defaultReapInterval,
+		matches:             make(map[string]*match),
+		done:                make(chan struct{}),
+		watchers:            make(map[chan DashboardEvent]bool),
+		handlers:            make(map[string]Handler),
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+	m.metrics = newMetrics(m)
+	m.registerRPC()
+
+	return m
 }
 
 // Error sends an error in the dedicated channel.
@@ -92,10 +168,22 @@ func (m *Manager) Start() error {
 		transportName := client.Transport().Name()
 		// In our example clients connect with JSON protocol but it can also be Protobuf.
 		transportProto := client.Transport().Protocol()
-		m.log.Info().Msgf("client %s (%s) connected via %s (%s)", client.ID(), string(client.Info()), transportName, transportProto)
+		m.log.Info().Str(logging.ClientID, client.ID()).Msgf("client (%s) connected via %s (%s)", string(client.Info()), transportName, transportProto)
+		m.metrics.clients.Inc()
 
 		client.OnSubscribe(func(e centrifuge.SubscribeEvent, cb centrifuge.SubscribeCallback) {
-			m.log.Info().Msgf("client %s (%s) subscribes on channel %s", client.ID(), string(e.Data), e.Channel)
+			if e.Channel == utils.AdminLogsChannel {
+				if !m.logsAllowed(e.Data) {
+					m.log.Warn().Str(logging.ClientID, client.ID()).Str(logging.Channel, e.Channel).Msg("client is not allowed to subscribe")
+					cb(centrifuge.SubscribeReply{}, centrifuge.ErrorPermissionDenied)
+					return
+				}
+				m.log.Info().Str(logging.ClientID, client.ID()).Str(logging.Channel, e.Channel).Msg("admin subscribes")
+				cb(centrifuge.SubscribeReply{}, nil)
+				return
+			}
+
+			m.log.Info().Str(logging.ClientID, client.ID()).Str(logging.Channel, e.Channel).Msgf("client (%s) subscribes", string(e.Data))
 
 			if len(e.Data) != 0 {
 				var iddata IDData
@@ -103,7 +191,10 @@ func (m *Manager) Start() error {
 				if err != nil {
 					m.log.Error().Msgf("error while unmarshaling subscribe data: %s", err.Error())
 				} else {
+					m.mu.Lock()
 					m.playersToClientsMap[iddata.ID] = client
+					m.mu.Unlock()
+					m.playerConnected(iddata.ID)
 				}
 			}
 
@@ -111,15 +202,39 @@ func (m *Manager) Start() error {
 		})
 
 		client.OnPublish(func(e centrifuge.PublishEvent, cb centrifuge.PublishCallback) {
-			m.log.Info().Msgf("client %s (%s) publishes into channel %s: %s", client.ID(), string(client.Info()), e.Channel, string(e.Data))
+			if e.Channel == utils.AdminLogsChannel {
+				cb(centrifuge.PublishReply{}, centrifuge.ErrorPermissionDenied)
+				return
+			}
+
+			m.log.Info().Str(logging.ClientID, client.ID()).Str(logging.Channel, e.Channel).Msgf("client (%s) publishes: %s", string(client.Info()), string(e.Data))
+			m.metrics.publications.WithLabelValues(channelType(e.Channel)).Inc()
 			cb(centrifuge.PublishReply{}, nil)
 		})
 
 		client.OnDisconnect(func(e centrifuge.DisconnectEvent) {
-			m.log.Info().Msgf("client %s (%s) disconnected", client.ID(), string(client.Info()))
+			m.log.Info().Str(logging.ClientID, client.ID()).Msgf("client (%s) disconnected", string(client.Info()))
+			m.metrics.clients.Dec()
+
+			m.mu.Lock()
+			disconnected := []string{}
+			for pID, c := range m.playersToClientsMap {
+				if c == client {
+					disconnected = append(disconnected, pID)
+					delete(m.playersToClientsMap, pID)
+				}
+			}
+			m.mu.Unlock()
+
+			for _, pID := range disconnected {
+				m.playerDisconnected(pID)
+			}
+			m.unbindPlayers(client.ID())
 		})
 
-		client.OnRPC(m.HandleRPC)
+		client.OnRPC(func(e centrifuge.RPCEvent, cb centrifuge.RPCCallback) {
+			m.HandleRPC(withClient(context.Background(), client.ID()), e, cb)
+		})
 	})
 
 	// Run node. This method does not block. See also node.Shutdown method
@@ -128,6 +243,7 @@ func (m *Manager) Start() error {
 	if err != nil {
 		return fmt.Errorf("error running centrifuge node: %s", err.Error())
 	}
+	m.running.Store(true)
 
 	// Configure HTTP routes.
 	// Serve Websocket connections using WebsocketHandler.
@@ -137,25 +253,68 @@ func (m *Manager) Start() error {
 	})
 	http.Handle("/connection/websocket", auth(wsHandler))
 
-	// The second route is for serving index.html file.
+	// Prometheus metrics.
+	http.Handle("/metrics", m.metrics.handler())
+
+	// Admin API, restricted to the admins.
+	if m.adminToken != "" {
+		http.Handle(AdminAPIPath, m.adminAPI())
+	}
+
+	// Health and readiness probes.
+	http.HandleFunc("/healthz", m.healthz)
+	http.HandleFunc("/readyz", m.readyz)
+
+	// The last route is for serving index.html file.
 	http.Handle("/", http.FileServer(http.Dir("./public")))
 
+	// Bind the listener before returning, so that the server is available
+	// as soon as the manager is started.
+	ln, err := net.Listen("tcp", defaultAddr)
+	if err != nil {
+		_ = m.node.Shutdown(context.Background())
+		m.running.Store(false)
+		return fmt.Errorf("error listening on %s: %s", defaultAddr, err.Error())
+	}
+	m.server = &http.Server{}
+
+	go m.matchmake()
+	go m.sweepLobbies()
+	go m.reap()
+	if m.logStream != nil {
+		go m.streamLogs()
+	}
+
 	go func() {
-		m.log.Info().Msgf("starting server, visit http://localhost:8000")
-		if err := http.ListenAndServe(":8000", nil); err != nil {
-			m.err <- fmt.Errorf("error listening on :8000: %s", err.Error())
+		m.log.Info().Msgf("starting server, visit http://localhost%s", defaultAddr)
+		if err := m.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			m.err <- fmt.Errorf("error serving on %s: %s", defaultAddr, err.Error())
 		}
 	}()
 
 	return nil
 }
 
+// Shutdown drains and stops the server. Only the first call shuts it down,
+// the next ones return immediately. The node and the server are only
+// stopped if Start created them.
 func (m *Manager) Shutdown() {
+	if !m.draining.CompareAndSwap(false, true) {
+		return
+	}
+
 	m.log.Info().Msg("shuting down ...")
 	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
 	defer cancel()
 
-	_ = m.node.Shutdown(ctx)
+	close(m.done)
+	if m.node != nil {
+		_ = m.node.Shutdown(ctx)
+	}
+	m.running.Store(false)
+	if m.server != nil {
+		_ = m.server.Shutdown(ctx)
+	}
 
 	m.log.Info().Msgf("stopped")
 }
@@ -1,19 +1,29 @@
 package manager_test
 
 import (
-	"fmt"
 	"os"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
 	"github.com/jtbonhomme/gameserver-websocket/internal/storage/memory"
 	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 var mgr *manager.Manager
+var store *memory.Memory
+
+// spans records the spans of the test manager.
+var spans = tracetest.NewSpanRecorder()
+
+// adminToken is the token of the admins of the test manager.
+const adminToken = "admin"
 
 type Response struct {
 	Status string `json:"status"`
@@ -22,13 +32,10 @@ type Response struct {
 
 func newLogger() zerolog.Logger {
 	// Init logger
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	output := zerolog.ConsoleWriter{
-		Out:           os.Stderr,
-		TimeFormat:    time.RFC3339,
-		FormatMessage: func(i interface{}) string { return fmt.Sprintf("[client] %s", i) },
+	log, err := logging.New(logging.Config{Level: "debug"})
+	if err != nil {
+		panic(err)
 	}
-	log := zerolog.New(output).With().Timestamp().Logger()
 
 	return log
 }
@@ -37,12 +44,22 @@ func TestMain(m *testing.M) {
 	var wg sync.WaitGroup
 	var err error
 
-	log := newLogger()
+	// the logs of the manager are streamed to the admins
+	stream, err := logging.NewStream("info", "manager", logging.DefaultStreamSize)
+	if err != nil {
+		panic(err)
+	}
+	log, err := logging.New(logging.Config{Level: "debug", Stream: stream})
+	if err != nil {
+		panic(err)
+	}
 	log.Info().Msg("start client")
 
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+
 	// concrete memory test storage implementation
-	s := memory.New(&log)
-	mgr = manager.New(&log, s)
+	store = memory.New(&log)
+	mgr = manager.New(&log, store, manager.WithSeedAllowed(), manager.WithBotTakeover(100*time.Millisecond), manager.WithAdminToken(adminToken), manager.WithMatchmaking(20*time.Millisecond, 300*time.Millisecond), manager.WithRatingTolerance(100, 500), manager.WithReadyCountdown(100*time.Millisecond), manager.WithLobbyExpiry(0, 20*time.Millisecond), manager.WithGameRetention(time.Second, 20*time.Millisecond), manager.WithLogStream(stream))
 	err = mgr.Start()
 	if err != nil {
 		log.Err(err).Msg("error starting manager")
@@ -65,5 +82,17 @@ func TestMain(m *testing.M) {
 	exitVal := m.Run()
 	c.Close()
 
+	// shutting the manager down twice must not panic
+	mgr.Shutdown()
+	mgr.Shutdown()
+
 	os.Exit(exitVal)
 }
+
+func TestShutdownNotStarted(t *testing.T) {
+	log := newLogger()
+	m := manager.New(&log, memory.New(&log))
+
+	// a manager never started shuts down without panicking
+	m.Shutdown()
+}
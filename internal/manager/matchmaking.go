@@ -0,0 +1,390 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+)
+
+const (
+	defaultMatchInterval  = 1 * time.Second
+	defaultConfirmTimeout = 30 * time.Second
+	// defaultRatingTolerance is the rating gap accepted by players entering
+	// the queue, widened by defaultRatingWidening per second of queue time.
+	defaultRatingTolerance float64 = 100
+	defaultRatingWidening  float64 = 10
+	// MinTableSize and MaxTableSize bound the table sizes players may queue
+	// for, as played with a standard Skyjo deck.
+	MinTableSize int = 2
+	MaxTableSize int = 8
+)
+
+// ticket is a player waiting in the matchmaking queue for a table size.
+type ticket struct {
+	pID    string
+	size   int
+	rating float64
+	since  time.Time
+}
+
+// match is a game created by the matchmaker, waiting for its players to
+// confirm, then starting once they all did.
+type match struct {
+	game      *games.Game
+	tickets   []ticket
+	confirmed map[string]bool
+	timer     *time.Timer
+	starting  bool
+}
+
+// WithMatchmaking sets how often the matchmaker groups queued players, and
+// how long matched players have to confirm before the match is canceled.
+func WithMatchmaking(interval, confirmTimeout time.Duration) Option {
+	return func(m *Manager) {
+		m.matchInterval = interval
+		m.confirmTimeout = confirmTimeout
+	}
+}
+
+// WithRatingTolerance sets the rating gap accepted by players entering the
+// queue, and how much it widens per second of queue time, so that players
+// waiting for long are matched with opponents of any strength.
+func WithRatingTolerance(tolerance, widening float64) Option {
+	return func(m *Manager) {
+		m.ratingTolerance = tolerance
+		m.ratingWidening = widening
+	}
+}
+
+// matchmake groups the queued players until the manager shuts down.
+func (m *Manager) matchmake() {
+	ticker := time.NewTicker(m.matchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.matchQueue()
+		}
+	}
+}
+
+// matchQueue groups the queued players asking for the same table size and
+// of similar ratings, first come first served, and seats each full group in
+// a new game.
+func (m *Manager) matchQueue() {
+	now := time.Now()
+
+	m.mu.Lock()
+	groups := [][]ticket{}
+	grouped := make(map[string]bool)
+	for i, first := range m.queue {
+		if grouped[first.pID] {
+			continue
+		}
+
+		group := []ticket{first}
+		for _, t := range m.queue[i+1:] {
+			if len(group) == first.size {
+				break
+			}
+			if !grouped[t.pID] && t.size == first.size && m.matchable(group, t, now) {
+				group = append(group, t)
+			}
+		}
+
+		if len(group) == first.size {
+			groups = append(groups, group)
+			for _, t := range group {
+				grouped[t.pID] = true
+			}
+		}
+	}
+
+	queue := []ticket{}
+	for _, t := range m.queue {
+		if !grouped[t.pID] {
+			queue = append(queue, t)
+		}
+	}
+	m.queue = queue
+	m.mu.Unlock()
+
+	for _, group := range groups {
+		ctx, span := tracing.Start(context.Background(), "matchmaking.seat")
+		err := m.seat(ctx, group)
+		if err != nil {
+			m.log.Error().Msgf("matchmaking error: %s", err.Error())
+			span.SetStatus(codes.Error, err.Error())
+			m.requeue(group)
+		}
+		span.End()
+	}
+}
+
+// matchable returns true if a queued player and each player of a group
+// accept the rating gap between them.
+func (m *Manager) matchable(group []ticket, t ticket, now time.Time) bool {
+	for _, g := range group {
+		gap := math.Abs(g.rating - t.rating)
+		if gap > m.tolerance(g, now) || gap > m.tolerance(t, now) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tolerance returns the rating gap accepted by a queued player, which
+// widens with its queue time.
+func (m *Manager) tolerance(t ticket, now time.Time) float64 {
+	return m.ratingTolerance + m.ratingWidening*now.Sub(t.since).Seconds()
+}
+
+// seat creates the game of a group of players, seats them, and asks them to
+// confirm the match. Matched games are private, so that no other player joins.
+func (m *Manager) seat(ctx context.Context, group []ticket) error {
+	code, err := m.newInviteCode(ctx)
+	if err != nil {
+		return err
+	}
+
+	size := len(group)
+	game, err := m.storage(ctx).CreateGame(size, size, games.WithInviteCode(code))
+	if err != nil {
+		return fmt.Errorf("unable to create game: %s", err.Error())
+	}
+	game.OnEvent(m.handleGameEvent(game))
+
+	for _, t := range group {
+		err = m.storage(ctx).JoinGame(game.ID.String(), t.pID)
+		if err != nil {
+			m.dropGame(ctx, game)
+			return fmt.Errorf("unable to seat player %s: %s", t.pID, err.Error())
+		}
+	}
+
+	gameID := game.ID.String()
+	m.mu.Lock()
+	m.matches[gameID] = &match{
+		game:      game,
+		tickets:   group,
+		confirmed: make(map[string]bool),
+		timer: time.AfterFunc(m.confirmTimeout, func() {
+			m.cancelMatch(ctx, gameID, "")
+		}),
+	}
+	m.mu.Unlock()
+
+	m.log.Info().Str(logging.GameID, game.ID.String()).Msgf("%d players matched", size)
+	for _, t := range group {
+		m.send(ctx, t.pID, []byte(`{"type": "matched", "emitter": "manager", "id": "`+gameID+`", "data": ""}`))
+	}
+
+	return nil
+}
+
+// requeue puts tickets back in the queue, at their original position.
+func (m *Manager) requeue(tickets []ticket) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queue = append(m.queue, tickets...)
+	sort.SliceStable(m.queue, func(i, j int) bool {
+		return m.queue[i].since.Before(m.queue[j].since)
+	})
+}
+
+// cancelMatch cancels a match whose players did not all confirm in time,
+// or which a player declined, and drops its game. The players who confirmed,
+// and the players who did not answer yet when a player declined, are put
+// back in the queue. It returns false if no match of the game was pending,
+// or if it is starting.
+func (m *Manager) cancelMatch(ctx context.Context, gameID, declined string) bool {
+	m.mu.Lock()
+	mt, ok := m.matches[gameID]
+	if !ok || mt.starting {
+		m.mu.Unlock()
+		return false
+	}
+	delete(m.matches, gameID)
+	mt.timer.Stop()
+	m.mu.Unlock()
+
+	requeued := []ticket{}
+	for _, t := range mt.tickets {
+		if t.pID != declined && (declined != "" || mt.confirmed[t.pID]) {
+			requeued = append(requeued, t)
+		}
+	}
+	m.requeue(requeued)
+
+	m.log.Info().Str(logging.GameID, mt.game.ID.String()).Msgf("match canceled, %d players queued again", len(requeued))
+	for _, t := range mt.tickets {
+		_ = mt.game.RemovePlayer(t.pID)
+
+		data := ""
+		for _, r := range requeued {
+			if r.pID == t.pID {
+				data = "queued"
+			}
+		}
+		m.send(ctx, t.pID, []byte(`{"type": "unmatched", "emitter": "manager", "id": "`+gameID+`", "data": "`+data+`"}`))
+	}
+	m.dropGame(ctx, mt.game)
+
+	return true
+}
+
+// dropGame removes the game of a match which did not start, and disconnects it.
+func (m *Manager) dropGame(ctx context.Context, game *games.Game) {
+	err := m.storage(ctx).RemoveGame(game.ID.String())
+	if err != nil {
+		m.log.Error().Str(logging.GameID, game.ID.String()).Msgf("unable to remove game: %s", err.Error())
+	}
+	m.releaseBots(ctx, game)
+	game.Close()
+}
+
+// queued returns true if a player is in the queue or in a match waiting
+// for confirmation. It must be called with the manager lock held.
+func (m *Manager) queued(pID string) bool {
+	for _, t := range m.queue {
+		if t.pID == pID {
+			return true
+		}
+	}
+
+	return m.matchOf(pID) != ""
+}
+
+// matchOf returns the ID of the game of the match waiting for a player
+// to confirm, or an empty string. It must be called with the manager lock held.
+func (m *Manager) matchOf(pID string) string {
+	for gameID, mt := range m.matches {
+		for _, t := range mt.tickets {
+			if t.pID == pID {
+				return gameID
+			}
+		}
+	}
+
+	return ""
+}
+
+// leaveQueue removes a player from the queue, or declines its pending match.
+// It returns false if the player was not queued.
+func (m *Manager) leaveQueue(ctx context.Context, pID string) bool {
+	m.mu.Lock()
+	for i, t := range m.queue {
+		if t.pID == pID {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			m.mu.Unlock()
+			return true
+		}
+	}
+	gameID := m.matchOf(pID)
+	m.mu.Unlock()
+
+	if gameID == "" {
+		return false
+	}
+
+	m.cancelMatch(ctx, gameID, pID)
+
+	return true
+}
+
+type QueueData struct {
+	IDPlayer uuid.UUID `json:"idPlayer"`
+	Size     int       `json:"size"`
+}
+
+// EnterQueue puts a player in the matchmaking queue, waiting for a game
+// of a given table size.
+func (m *Manager) EnterQueue(ctx context.Context, queueData QueueData) (Empty, error) {
+	pID := queueData.IDPlayer.String()
+	player, err := m.storage(ctx).PlayerByID(pID)
+	if err != nil {
+		return Empty{}, fmt.Errorf("unknown player %s: %s", pID, err.Error())
+	}
+
+	if queueData.Size < MinTableSize || queueData.Size > MaxTableSize {
+		return Empty{}, fmt.Errorf("invalid table size %d: must be between %d and %d", queueData.Size, MinTableSize, MaxTableSize)
+	}
+
+	m.mu.Lock()
+	if m.queued(pID) {
+		m.mu.Unlock()
+		return Empty{}, fmt.Errorf("player %s already queued", pID)
+	}
+	m.queue = append(m.queue, ticket{pID: pID, size: queueData.Size, rating: player.Rating, since: time.Now()})
+	m.mu.Unlock()
+
+	m.log.Debug().Str(logging.PlayerID, pID).Msgf("player queued for %d players", queueData.Size)
+
+	return Empty{}, nil
+}
+
+// LeaveQueue removes a player from the matchmaking queue. A player matched
+// but who did not confirm yet declines the match.
+func (m *Manager) LeaveQueue(ctx context.Context, queueData QueueData) (Empty, error) {
+	if !m.leaveQueue(ctx, queueData.IDPlayer.String()) {
+		return Empty{}, fmt.Errorf("player %s not queued", queueData.IDPlayer.String())
+	}
+
+	return Empty{}, nil
+}
+
+// ConfirmMatch confirms that a matched player is ready to play. The game
+// starts once all its players confirmed. The match is canceled if the game
+// fails to start, and its players are put back in the queue.
+func (m *Manager) ConfirmMatch(ctx context.Context, confirmData GamePlayerData) (Empty, error) {
+	gameID, pID := confirmData.IDGame.String(), confirmData.IDPlayer.String()
+
+	m.mu.Lock()
+	mt, ok := m.matches[gameID]
+	if !ok || mt.starting || m.matchOf(pID) != gameID {
+		m.mu.Unlock()
+		return Empty{}, fmt.Errorf("no match of player %s waiting for confirmation in game %s", pID, gameID)
+	}
+
+	mt.confirmed[pID] = true
+	ready := len(mt.confirmed) == len(mt.tickets)
+	if ready {
+		mt.starting = true
+		mt.timer.Stop()
+	}
+	m.mu.Unlock()
+
+	if !ready {
+		return Empty{}, nil
+	}
+
+	err := m.startGame(ctx, mt.game)
+
+	m.mu.Lock()
+	mt.starting = false
+	if err == nil {
+		delete(m.matches, gameID)
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		m.cancelMatch(ctx, gameID, "")
+		return Empty{}, fmt.Errorf("unable to start game %s: %s", gameID, err.Error())
+	}
+
+	return Empty{}, nil
+}
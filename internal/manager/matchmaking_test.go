@@ -0,0 +1,203 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// registerPlayers registers new players, and returns their IDs.
+func registerPlayers(t *testing.T, names ...string) []string {
+	ids := []string{}
+	for _, name := range names {
+		response := call(t, rpc(manager.RegisterPlayer), `{"name": "`+name+`"}`)
+		var player players.Player
+		err := json.Unmarshal([]byte(response.Result), &player)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+		ids = append(ids, player.ID.String())
+	}
+
+	return ids
+}
+
+// matchedGame returns the game a player was seated in by the matchmaker.
+func matchedGame(t *testing.T, pID string) *games.Game {
+	var matched *games.Game
+	waitFor(t, "player matched", func() bool {
+		for _, game := range store.ListGames() {
+			if game.IsPrivate() && !game.IsStarted() && utils.ContainsString(game.Players(), pID) {
+				matched = game
+				return true
+			}
+		}
+		return false
+	})
+
+	return matched
+}
+
+func TestEnterQueue(t *testing.T) {
+	ids := registerPlayers(t, "queue1")
+	defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+ids[0]+`", "token": "`+adminToken+`"}`)
+
+	for _, size := range []string{"0", "1", "100"} {
+		response := call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+ids[0]+`", "size": `+size+`}`)
+		if response.Status != "ko" {
+			t.Errorf("expected error while queuing for %s players", size)
+		}
+	}
+
+	response := call(t, rpc(manager.EnterQueue), `{"idPlayer": "00000000-0000-0000-0000-000000000000", "size": 2}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while queuing an unknown player")
+	}
+
+	response = call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+ids[0]+`", "size": 5}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while queuing: %s", response.Result)
+	}
+
+	response = call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+ids[0]+`", "size": 5}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while queuing twice")
+	}
+
+	response = call(t, rpc(manager.LeaveQueue), `{"idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ok" {
+		t.Errorf("unexpected error while leaving queue: %s", response.Result)
+	}
+
+	response = call(t, rpc(manager.LeaveQueue), `{"idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while leaving queue twice")
+	}
+}
+
+func TestMatchmaking(t *testing.T) {
+	ids := registerPlayers(t, "match1", "match2")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	for _, id := range ids {
+		response := call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+id+`", "size": 2}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while queuing: %s", response.Result)
+		}
+	}
+
+	game := matchedGame(t, ids[0])
+	if !utils.ContainsString(game.Players(), ids[1]) {
+		t.Fatalf("expected players to be matched together, got %v", game.Players())
+	}
+
+	// matched players can not queue again
+	response := call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+ids[0]+`", "size": 2}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while queuing a matched player")
+	}
+
+	response = call(t, rpc(manager.ConfirmMatch), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while confirming match: %s", response.Result)
+	}
+	if game.IsStarted() {
+		t.Errorf("expected game not to start before all players confirmed")
+	}
+
+	response = call(t, rpc(manager.ConfirmMatch), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while confirming match: %s", response.Result)
+	}
+	if !game.IsStarted() {
+		t.Errorf("expected game to start once all players confirmed")
+	}
+
+	response = call(t, rpc(manager.ConfirmMatch), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while confirming a started match")
+	}
+}
+
+func TestMatchTimeout(t *testing.T) {
+	ids := registerPlayers(t, "timeout1", "timeout2")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	for _, id := range ids {
+		response := call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+id+`", "size": 3}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while queuing: %s", response.Result)
+		}
+	}
+
+	extra := registerPlayers(t, "timeout3")
+	defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+extra[0]+`", "token": "`+adminToken+`"}`)
+	response := call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+extra[0]+`", "size": 3}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while queuing: %s", response.Result)
+	}
+
+	game := matchedGame(t, ids[0])
+	response = call(t, rpc(manager.ConfirmMatch), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while confirming match: %s", response.Result)
+	}
+
+	// players who did not confirm in time leave the queue
+	waitFor(t, "match canceled", func() bool {
+		_, err := store.GameByID(game.ID.String())
+		return len(game.Players()) == 0 && err != nil
+	})
+
+	response = call(t, rpc(manager.LeaveQueue), `{"idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ok" {
+		t.Errorf("expected player who confirmed to be queued again: %s", response.Result)
+	}
+	for _, id := range []string{ids[1], extra[0]} {
+		response = call(t, rpc(manager.LeaveQueue), `{"idPlayer": "`+id+`"}`)
+		if response.Status != "ko" {
+			t.Errorf("expected player who did not confirm to leave the queue")
+		}
+	}
+}
+
+func TestDeclineMatch(t *testing.T) {
+	ids := registerPlayers(t, "decline1", "decline2")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	for _, id := range ids {
+		response := call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+id+`", "size": 2}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while queuing: %s", response.Result)
+		}
+	}
+
+	game := matchedGame(t, ids[0])
+	response := call(t, rpc(manager.LeaveQueue), `{"idPlayer": "`+ids[0]+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while declining match: %s", response.Result)
+	}
+	if len(game.Players()) != 0 {
+		t.Errorf("expected declined match to free its seats, got %v", game.Players())
+	}
+	_, err := store.GameByID(game.ID.String())
+	if err == nil {
+		t.Errorf("expected game of the declined match to be removed")
+	}
+
+	// the other player is queued again
+	response = call(t, rpc(manager.LeaveQueue), `{"idPlayer": "`+ids[1]+`"}`)
+	if response.Status != "ok" {
+		t.Errorf("expected other player to be queued again: %s", response.Result)
+	}
+}
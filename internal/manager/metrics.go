@@ -0,0 +1,158 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+const metricsNamespace = "gameserver"
+
+// metrics are the Prometheus metrics of the game server.
+type metrics struct {
+	registry     *prometheus.Registry
+	clients      prometheus.Gauge
+	rpcs         *prometheus.CounterVec
+	rpcDurations *prometheus.HistogramVec
+	publications *prometheus.CounterVec
+	timeouts     *prometheus.CounterVec
+}
+
+// newMetrics registers the metrics of the manager in a dedicated registry.
+func newMetrics(m *Manager) *metrics {
+	mt := &metrics{
+		registry: prometheus.NewRegistry(),
+		clients: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "connected_clients",
+			Help:      "Number of clients connected to the server.",
+		}),
+		rpcs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "rpc_total",
+			Help:      "Number of RPC handled, by method and status.",
+		}, []string{"method", "status"}),
+		rpcDurations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Duration of the RPC, by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		publications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "publications_total",
+			Help:      "Number of publications, by channel type (server, game, logs or client).",
+		}, []string{"channel"}),
+		timeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "timeouts_total",
+			Help:      "Number of players who did not answer in time, by kind (init or turn).",
+		}, []string{"kind"}),
+	}
+
+	mt.registry.MustRegister(
+		mt.clients,
+		mt.rpcs,
+		mt.rpcDurations,
+		mt.publications,
+		mt.timeouts,
+		&storeCollector{m: m},
+	)
+
+	if m.logStream != nil {
+		mt.registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "log_entries_dropped_total",
+			Help:      "Number of log entries not streamed to the admins, because the stream buffer was full.",
+		}, func() float64 { return float64(m.logStream.Dropped()) }))
+	}
+
+	return mt
+}
+
+// handler serves the metrics of the manager, along with the metrics of the
+// centrifuge node and of the Go runtime.
+func (mt *metrics) handler() http.Handler {
+	return promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, mt.registry}, promhttp.HandlerOpts{})
+}
+
+// observe is the middleware counting and timing the RPC.
+func (mt *metrics) observe(method string, next Handler) Handler {
+	return func(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+		next(ctx, data, mt.observeRPC(method, c))
+	}
+}
+
+// observeRPC wraps the callback of a RPC, so that the RPC is counted with
+// the status of its reply, and timed, when the reply is sent.
+func (mt *metrics) observeRPC(method string, c centrifuge.RPCCallback) centrifuge.RPCCallback {
+	start := time.Now()
+	return func(reply centrifuge.RPCReply, err error) {
+		var response struct {
+			Status string `json:"status"`
+		}
+		if err != nil || json.Unmarshal(reply.Data, &response) != nil || response.Status == "" {
+			response.Status = KO
+		}
+
+		mt.rpcs.WithLabelValues(method, response.Status).Inc()
+		mt.rpcDurations.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		c(reply, err)
+	}
+}
+
+// channelType returns the type of a channel: the server channel, the logs
+// channel, or a game channel.
+func channelType(channel string) string {
+	switch channel {
+	case utils.ServerPublishChannel:
+		return "server"
+	case utils.AdminLogsChannel:
+		return "logs"
+	}
+	return "game"
+}
+
+var (
+	gamesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "games"),
+		"Number of live games, by state (lobby, running or over).",
+		[]string{"state"}, nil,
+	)
+	playersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "registered_players"),
+		"Number of registered players.",
+		nil, nil,
+	)
+)
+
+// storeCollector collects the games and players of the storage when scraped.
+type storeCollector struct {
+	m *Manager
+}
+
+// Describe implements prometheus.Collector.
+func (sc *storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- gamesDesc
+	ch <- playersDesc
+}
+
+// Collect implements prometheus.Collector.
+func (sc *storeCollector) Collect(ch chan<- prometheus.Metric) {
+	states := map[string]int{"lobby": 0, "running": 0, "over": 0}
+	for _, game := range sc.m.store.ListGames() {
+		states[gameState(game)]++
+	}
+	for state, n := range states {
+		ch <- prometheus.MustNewConstMetric(gamesDesc, prometheus.GaugeValue, float64(n), state)
+	}
+
+	ch <- prometheus.MustNewConstMetric(playersDesc, prometheus.GaugeValue, float64(len(sc.m.store.ListPlayers())))
+}
@@ -0,0 +1,76 @@
+package manager_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/centrifugal/centrifuge"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+)
+
+// rpc returns a handler calling a method through the RPC dispatcher.
+func rpc(method string) func(context.Context, []byte, centrifuge.RPCCallback) {
+	return func(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+		mgr.HandleRPC(ctx, centrifuge.RPCEvent{Method: method, Data: data}, c)
+	}
+}
+
+func scrape(t *testing.T) string {
+	resp, err := http.Get("http://localhost:8000/metrics")
+	if err != nil {
+		t.Fatalf("unexpected error while scraping metrics: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error while reading metrics: %s", err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected metrics to be served, got %d: %s", resp.StatusCode, string(b))
+	}
+
+	return string(b)
+}
+
+func TestMetrics(t *testing.T) {
+	response := call(t, rpc(manager.RegisterPlayer), `{"name": "metered"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while registering player: %s", response.Result)
+	}
+	var player players.Player
+	err := json.Unmarshal([]byte(response.Result), &player)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+	defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+player.ID.String()+`", "token": "`+adminToken+`"}`)
+
+	response = call(t, rpc(manager.StartGame), `{"id": "unknown"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected an error while starting an unknown game")
+	}
+
+	_ = call(t, rpc("unknownMethod"), `{}`)
+
+	metrics := scrape(t)
+	for _, expected := range []string{
+		`gameserver_rpc_total{method="registerPlayer",status="ok"}`,
+		`gameserver_rpc_total{method="startGame",status="ko"}`,
+		`gameserver_rpc_total{method="unsupported",status="ko"}`,
+		`gameserver_rpc_duration_seconds_count{method="registerPlayer"}`,
+		`gameserver_publications_total{channel="server"}`,
+		`gameserver_connected_clients `,
+		`gameserver_games{state="lobby"}`,
+		`gameserver_registered_players`,
+	} {
+		if !strings.Contains(metrics, expected) {
+			t.Errorf("expected metrics to contain %s, got:\n%s", expected, metrics)
+		}
+	}
+}
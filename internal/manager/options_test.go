@@ -0,0 +1,68 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+)
+
+func TestGameOptions(t *testing.T) {
+	response := call(t, rpc(manager.CreateGame), `{"minPlayers": 2, "maxPlayers": 2, "options": {"scoreLimit": -1}}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while creating a game with invalid options")
+	}
+
+	// the standard deck deals the boards of up to 12 players
+	for _, data := range []string{
+		`{"minPlayers": 2, "maxPlayers": 13}`,
+		`{"minPlayers": 1, "maxPlayers": 4}`,
+		`{"minPlayers": 3, "maxPlayers": 2}`,
+		`{"minPlayers": 2, "maxPlayers": -1}`,
+	} {
+		response = call(t, rpc(manager.CreateGame), data)
+		if response.Status != "ko" {
+			t.Errorf("expected error while creating a game with %s", data)
+		}
+	}
+
+	response = call(t, rpc(manager.CreateGame), `{"minPlayers": 2, "maxPlayers": 2, "options": {"scoreLimit": 50, "columnClears": false}}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while creating game: %s", response.Result)
+	}
+
+	var game games.Game
+	err := json.Unmarshal([]byte(response.Result), &game)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	response = call(t, rpc(manager.ListGames), `{}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while listing games: %s", response.Result)
+	}
+
+	var list []*games.Game
+	err = json.Unmarshal([]byte(response.Result), &list)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	found := false
+	for _, g := range list {
+		if g.ID != game.ID {
+			continue
+		}
+		found = true
+
+		o := g.Options
+		if o.ScoreLimit != 50 || o.ColumnClears || !o.DoublePenalty || o.InitialReveals != games.InitialReveals {
+			t.Errorf("unexpected options %+v", o)
+		}
+	}
+
+	if !found {
+		t.Errorf("expected game %s in games list", game.ID.String())
+	}
+}
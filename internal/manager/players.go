@@ -1,108 +1,104 @@
 package manager
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
-	"github.com/centrifugal/centrifuge"
 	"github.com/google/uuid"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 	"github.com/jtbonhomme/gameserver-websocket/internal/players"
-	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
 )
 
-// ListPlayers returns the list of all players.
-func (m *Manager) ListPlayers(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var b []byte
-	var err error
+// ListPlayers returns the list of all players but bots, with their IDs
+// blanked on copies of the registered players.
+func (m *Manager) ListPlayers(ctx context.Context, _ Empty) ([]*players.Player, error) {
+	listed := []*players.Player{}
+	for _, registered := range m.storage(ctx).ListPlayers() {
+		if m.isBot(registered.ID.String()) {
+			continue
+		}
+
+		player := *registered
+		player.ID = uuid.Nil
+		listed = append(listed, &player)
+	}
 
-	players := m.store.ListPlayers()
+	return listed, nil
+}
 
-	for i, _ := range players {
-		players[i].ID = uuid.Nil
+// RegisterPlayer handles new player registration. The player is bound to
+// the calling client, eg to manage the games it hosts, until the client
+// disconnects. Players registered again with their ID are bound to the new
+// client, unless their client is still connected.
+func (m *Manager) RegisterPlayer(ctx context.Context, player players.Player) (*players.Player, error) {
+	if m.isBanned(player.Name) {
+		return nil, fmt.Errorf("player %s is banned", player.Name)
 	}
 
-	b, err = json.Marshal(players)
+	registeredPlayer, err := m.storage(ctx).RegisterPlayer(player.ID.String(), player.Name)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to marshal data %v: %s", players, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
-		return
+		return nil, fmt.Errorf("unable to register player %s: %s", player.Name, err.Error())
 	}
 
-	status = OK
-	msg = string(b)
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
+	err = m.bindPlayer(ctx, registeredPlayer.ID.String())
+	if err != nil {
+		// a new player is not left registered without a client
+		if registeredPlayer.ID != player.ID {
+			_ = m.storage(ctx).UnregisterPlayer(registeredPlayer.ID.String())
+		}
+		return nil, fmt.Errorf("unable to register player %s: %s", registeredPlayer.ID.String(), err.Error())
+	}
+
+	m.publish(ctx, []byte(`{"type": "registration", "emitter": "manager", "id": "", "data": "`+registeredPlayer.Name+`"}`))
+
+	m.log.Debug().Str(logging.PlayerID, registeredPlayer.ID.String()).Msg("player registered")
+
+	return registeredPlayer, nil
 }
 
-// RegisterPlayer handles new player registration.
-func (m *Manager) RegisterPlayer(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var b []byte
-	var err error
+type PlayerData struct {
+	ID    uuid.UUID `json:"id"`
+	Token string    `json:"token,omitempty"`
+}
 
-	var player players.Player
-	err = json.Unmarshal(data, &player)
+// UnregisterPlayer removes a player from registry, once it left the queue
+// and its games.
+func (m *Manager) UnregisterPlayer(ctx context.Context, player PlayerData) (Empty, error) {
+	err := m.playerAllowed(ctx, player.ID.String(), player.Token)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
-		return
+		return Empty{}, err
 	}
 
-	registeredPlayer, err := m.store.RegisterPlayer(player.ID.String(), player.Name)
+	_, err = m.storage(ctx).PlayerByID(player.ID.String())
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to register player %v: %s", player, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
-		return
+		return Empty{}, fmt.Errorf("unable to unregister player %s: %s", player.ID.String(), err.Error())
 	}
 
-	b, err = json.Marshal(registeredPlayer)
-	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to marshal data %v: %s", player, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
-		return
-	}
+	m.leaveQueue(ctx, player.ID.String())
+	m.leaveGames(ctx, player.ID.String())
 
-	_, err = m.node.Publish(utils.ServerPublishChannel,
-		[]byte(`{"type": "registration", "emitter": "manager", "id": "", "data": "`+registeredPlayer.Name+`"}`))
+	err = m.storage(ctx).UnregisterPlayer(player.ID.String())
 	if err != nil {
-		m.log.Error().Msgf("manager publication error: %s", err.Error())
+		return Empty{}, fmt.Errorf("unable to unregister player %s: %s", player.ID.String(), err.Error())
 	}
 
-	status = OK
-	msg = string(b)
-	m.log.Debug().Msgf("[rpc] (player) registered: %s", msg)
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
-}
+	m.mu.Lock()
+	delete(m.playersToOwnersMap, player.ID.String())
+	m.mu.Unlock()
 
-// UnregisterPlayer removes a player from registry.
-func (m *Manager) UnregisterPlayer(data []byte, c centrifuge.RPCCallback) {
-	var status, msg string
-	var err error
+	m.log.Debug().Str(logging.PlayerID, player.ID.String()).Msg("player unregistered")
 
-	var player players.Player
-	err = json.Unmarshal(data, &player)
-	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
-		return
-	}
+	return Empty{}, nil
+}
 
-	err = m.store.UnregisterPlayer(player.ID.String())
+// playerName returns the name of a registered player, which the events of
+// the server channel carry rather than its ID.
+func (m *Manager) playerName(ctx context.Context, pID string) string {
+	player, err := m.storage(ctx).PlayerByID(pID)
 	if err != nil {
-		status = KO
-		msg = fmt.Sprintf("unable to unregister player %v: %s", player, err.Error())
-		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
-		return
+		return ""
 	}
 
-	status = OK
-	msg = "{}"
-	m.log.Debug().Msgf("[rpc] player unregistered %s", player.ID)
-	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result":%q}`, status, msg))}, nil)
+	return player.Name
 }
@@ -1,12 +1,14 @@
 package manager_test
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/centrifugal/centrifuge"
 	"github.com/google/uuid"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
 	"github.com/jtbonhomme/gameserver-websocket/internal/players"
 )
 
@@ -18,7 +20,7 @@ func TestPlayers(t *testing.T) {
 
 	// first time player registration
 	go func() {
-		mgr.RegisterPlayer([]byte(`{"name":"name1"}`), func(r centrifuge.RPCReply, e error) {
+		rpc(manager.RegisterPlayer)(context.Background(), []byte(`{"name":"name1"}`), func(r centrifuge.RPCReply, e error) {
 			replyChan <- r.Data
 			errChan <- e
 		})
@@ -47,7 +49,7 @@ func TestPlayers(t *testing.T) {
 	}
 
 	go func() {
-		mgr.RegisterPlayer([]byte(`{"name":"name2"}`), func(r centrifuge.RPCReply, e error) {
+		rpc(manager.RegisterPlayer)(context.Background(), []byte(`{"name":"name2"}`), func(r centrifuge.RPCReply, e error) {
 			replyChan <- r.Data
 			errChan <- e
 		})
@@ -78,7 +80,7 @@ func TestPlayers(t *testing.T) {
 	}
 
 	go func() {
-		mgr.RegisterPlayer([]byte(`{"id": "`+id+`", "name":"name1"}`), func(r centrifuge.RPCReply, e error) {
+		rpc(manager.RegisterPlayer)(context.Background(), []byte(`{"id": "`+id+`", "name":"name1"}`), func(r centrifuge.RPCReply, e error) {
 			replyChan <- r.Data
 			errChan <- e
 		})
@@ -110,7 +112,7 @@ func TestPlayers(t *testing.T) {
 	}
 
 	go func() {
-		mgr.ListPlayers([]byte(`{}`), func(r centrifuge.RPCReply, e error) {
+		rpc(manager.ListPlayers)(context.Background(), []byte(`{}`), func(r centrifuge.RPCReply, e error) {
 			replyChan <- r.Data
 			errChan <- e
 		})
@@ -143,8 +145,14 @@ func TestPlayers(t *testing.T) {
 		}
 	}
 
+	// the registered players keep their IDs
+	registered, err := store.PlayerByID(id)
+	if err != nil || registered.ID.String() != id {
+		t.Errorf("expected registered player to keep its ID %s, got %+v", id, registered)
+	}
+
 	go func() {
-		mgr.UnregisterPlayer([]byte(`{"id": "`+id+`"}`), func(r centrifuge.RPCReply, e error) {
+		rpc(manager.UnregisterPlayer)(context.Background(), []byte(`{"id": "`+id+`", "token": "`+adminToken+`"}`), func(r centrifuge.RPCReply, e error) {
 			replyChan <- r.Data
 			errChan <- e
 		})
@@ -162,7 +170,7 @@ func TestPlayers(t *testing.T) {
 	}
 
 	go func() {
-		mgr.ListPlayers([]byte(`{}`), func(r centrifuge.RPCReply, e error) {
+		rpc(manager.ListPlayers)(context.Background(), []byte(`{}`), func(r centrifuge.RPCReply, e error) {
 			replyChan <- r.Data
 			errChan <- e
 		})
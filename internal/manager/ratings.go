@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+)
+
+// rate updates the ratings of the players of a finished game, from their
+// ranking. Players unregistered since the game started are left out, and
+// games with less than two rated players are not rated.
+func (m *Manager) rate(ctx context.Context, game *games.Game) {
+	ratings := make(map[string]float64)
+	ranking := [][]string{}
+	for _, group := range game.Ranking() {
+		rated := []string{}
+		for _, pID := range group {
+			if m.isBot(pID) {
+				continue
+			}
+
+			player, err := m.storage(ctx).PlayerByID(pID)
+			if err != nil {
+				continue
+			}
+			ratings[pID] = player.Rating
+			rated = append(rated, pID)
+		}
+		if len(rated) > 0 {
+			ranking = append(ranking, rated)
+		}
+	}
+
+	if len(ratings) < 2 {
+		return
+	}
+
+	for pID, rating := range players.Rate(ratings, ranking) {
+		err := m.storage(ctx).RecordRating(pID, rating)
+		if err != nil {
+			m.log.Error().Str(logging.GameID, game.ID.String()).Str(logging.PlayerID, pID).Msgf("unable to record rating: %s", err.Error())
+			continue
+		}
+		m.log.Debug().Str(logging.GameID, game.ID.String()).Str(logging.PlayerID, pID).Msgf("player rated %.0f (was %.0f)", rating, ratings[pID])
+	}
+}
+
+// PlayerProfile returns the profile of a player, including its rating and
+// the number of rated games it played.
+func (m *Manager) PlayerProfile(ctx context.Context, idData IDData) (*players.Player, error) {
+	player, err := m.storage(ctx).PlayerByID(idData.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve player: %s", err.Error())
+	}
+
+	return player, nil
+}
@@ -0,0 +1,99 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games/dice"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// profile returns the profile of a player.
+func profile(t *testing.T, pID string) players.Player {
+	response := call(t, rpc(manager.PlayerProfile), `{"id": "`+pID+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while retrieving profile: %s", response.Result)
+	}
+
+	var player players.Player
+	err := json.Unmarshal([]byte(response.Result), &player)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	return player
+}
+
+func TestRatings(t *testing.T) {
+	game, ids := newLeaveGame(t, dice.Type, "rated1", "rated2")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	player := profile(t, ids[0])
+	if player.Rating != players.InitialRating || player.Games != 0 {
+		t.Errorf("expected new player rated %f after 0 game, got %f after %d", players.InitialRating, player.Rating, player.Games)
+	}
+
+	response := call(t, rpc(manager.PlayerProfile), `{"id": "`+utils.ServerPublishChannel+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while retrieving the profile of an unknown player")
+	}
+
+	response = call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	// the player left wins the game
+	response = call(t, rpc(manager.LeaveGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while leaving game: %s", response.Result)
+	}
+
+	waitFor(t, "players rated", func() bool {
+		return profile(t, ids[0]).Games == 1 && profile(t, ids[1]).Games == 1
+	})
+	if profile(t, ids[0]).Rating <= players.InitialRating {
+		t.Errorf("expected winner rating to rise, got %f", profile(t, ids[0]).Rating)
+	}
+	if profile(t, ids[1]).Rating >= players.InitialRating {
+		t.Errorf("expected loser rating to drop, got %f", profile(t, ids[1]).Rating)
+	}
+}
+
+func TestRatedMatchmaking(t *testing.T) {
+	ids := registerPlayers(t, "strong", "weak")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	err := store.RecordRating(ids[0], players.InitialRating+400)
+	if err != nil {
+		t.Fatalf("unexpected error while rating player: %s", err.Error())
+	}
+
+	for _, id := range ids {
+		response := call(t, rpc(manager.EnterQueue), `{"idPlayer": "`+id+`", "size": 2}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while queuing: %s", response.Result)
+		}
+	}
+
+	// players of different strengths are not matched at once
+	time.Sleep(100 * time.Millisecond)
+	for _, game := range store.ListGames() {
+		if utils.ContainsString(game.Players(), ids[0]) {
+			t.Fatalf("expected players of different strengths not to be matched at once")
+		}
+	}
+
+	// the tolerance widens with queue time
+	game := matchedGame(t, ids[0])
+	if !utils.ContainsString(game.Players(), ids[1]) {
+		t.Errorf("expected players to be matched together, got %v", game.Players())
+	}
+}
@@ -0,0 +1,133 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+)
+
+const defaultReadyCountdown = 5 * time.Second
+
+// WithReadyCountdown sets the countdown before games created with the auto
+// start option start, once all their players are ready.
+func WithReadyCountdown(countdown time.Duration) Option {
+	return func(m *Manager) {
+		m.readyCountdown = countdown
+	}
+}
+
+type ReadyData struct {
+	IDGame   uuid.UUID `json:"idGame"`
+	IDPlayer uuid.UUID `json:"idPlayer"`
+	Ready    bool      `json:"ready"`
+	Token    string    `json:"token,omitempty"`
+}
+
+// SetReady marks a player of the lobby of a game as ready to play or not.
+// Games created with the auto start option start after a countdown once all
+// their players are ready.
+func (m *Manager) SetReady(ctx context.Context, readyData ReadyData) (Empty, error) {
+	err := m.playerAllowed(ctx, readyData.IDPlayer.String(), readyData.Token)
+	if err != nil {
+		return Empty{}, err
+	}
+
+	game, err := m.storage(ctx).GameByID(readyData.IDGame.String())
+	if err != nil {
+		return Empty{}, fmt.Errorf("unable to retrieve game: %s", err.Error())
+	}
+
+	err = game.SetReady(readyData.IDPlayer.String(), readyData.Ready)
+	if err != nil {
+		return Empty{}, fmt.Errorf("unable to set player %s ready: %s", readyData.IDPlayer.String(), err.Error())
+	}
+
+	if game.Settings().AutoStart && game.AllReady() {
+		m.countdown(game)
+	}
+
+	return Empty{}, nil
+}
+
+// countdown starts a game at the end of the ready countdown, and publishes
+// the seconds left every second. The countdown is canceled, and a countdown
+// event without data is published, when a player is no longer ready, or when
+// a new player joins the lobby.
+func (m *Manager) countdown(game *games.Game) {
+	gameID := game.ID.String()
+
+	m.mu.Lock()
+	if m.countdowns[gameID] {
+		m.mu.Unlock()
+		return
+	}
+	m.countdowns[gameID] = true
+	m.mu.Unlock()
+
+	go func() {
+		ctx, span := tracing.Start(context.Background(), "game.countdown", tracing.GameID.String(gameID))
+		defer span.End()
+
+		// the countdown runs out of any RPC, whose handlers recover from panics
+		defer func() {
+			if r := recover(); r != nil {
+				m.log.Error().Str(logging.GameID, gameID).Msgf("countdown panics: %v\n%s", r, string(debug.Stack()))
+				span.SetStatus(codes.Error, fmt.Sprintf("countdown panics: %v", r))
+			}
+		}()
+
+		defer func() {
+			m.mu.Lock()
+			delete(m.countdowns, gameID)
+			m.mu.Unlock()
+		}()
+
+		for left := m.readyCountdown; ; left -= time.Second {
+			if game.IsStarted() {
+				return
+			}
+
+			if !game.AllReady() {
+				m.log.Info().Str(logging.GameID, game.ID.String()).Msg("countdown canceled")
+				m.publishCountdown(ctx, gameID, "")
+				return
+			}
+
+			if left <= 0 {
+				break
+			}
+
+			m.publishCountdown(ctx, gameID, strconv.Itoa(int(math.Ceil(left.Seconds()))))
+
+			wait := time.Second
+			if left < wait {
+				wait = left
+			}
+			select {
+			case <-m.done:
+				return
+			case <-time.After(wait):
+			}
+		}
+
+		err := m.startGame(ctx, game)
+		if err != nil {
+			m.log.Error().Str(logging.GameID, game.ID.String()).Msgf("unable to start game at the end of the countdown: %s", err.Error())
+		}
+	}()
+}
+
+// publishCountdown publishes the seconds left before a game starts.
+func (m *Manager) publishCountdown(ctx context.Context, gameID, seconds string) {
+	m.publish(ctx, []byte(`{"type": "countdown", "emitter": "manager", "id": "`+gameID+`", "data": "`+seconds+`"}`))
+}
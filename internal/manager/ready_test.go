@@ -0,0 +1,51 @@
+package manager_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+)
+
+func TestReadyCheck(t *testing.T) {
+	game, ids := newLeaveGame(t, games.Skyjo, "ready1", "ready2")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	response := call(t, rpc(manager.SetGameOptions), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`", "options": {"readyCheck": true, "autoStart": true}}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while setting options: %s", response.Result)
+	}
+
+	response = call(t, rpc(manager.SetReady), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[0]+`", "ready": true, "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while getting ready: %s", response.Result)
+	}
+
+	response = call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while starting a game whose players are not ready")
+	}
+
+	// the countdown is canceled when a player is no longer ready
+	call(t, rpc(manager.SetReady), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`", "ready": true, "token": "`+adminToken+`"}`)
+	call(t, rpc(manager.SetReady), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`", "ready": false, "token": "`+adminToken+`"}`)
+	time.Sleep(300 * time.Millisecond)
+	if game.IsStarted() {
+		t.Fatalf("expected game not to start when a player is no longer ready")
+	}
+
+	// the game starts at the end of the countdown once all players are ready
+	response = call(t, rpc(manager.SetReady), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`", "ready": true, "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while getting ready: %s", response.Result)
+	}
+	waitFor(t, "game started", game.IsStarted)
+
+	response = call(t, rpc(manager.SetReady), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`", "ready": false, "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while changing readiness in a started game")
+	}
+}
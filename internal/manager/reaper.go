@@ -0,0 +1,73 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+)
+
+const (
+	defaultGameRetention = 10 * time.Minute
+	defaultReapInterval  = 1 * time.Minute
+)
+
+// WithGameRetention sets how long games which are over, ie finished or
+// aborted, stay live before they are archived and removed, and how often
+// games are checked.
+func WithGameRetention(retention, interval time.Duration) Option {
+	return func(m *Manager) {
+		m.gameRetention = retention
+		m.reapInterval = interval
+	}
+}
+
+// reap archives the games over for longer than the retention until the
+// manager shuts down.
+func (m *Manager) reap() {
+	ticker := time.NewTicker(m.reapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			for _, game := range m.store.ListGames() {
+				if game.IsOver() && time.Since(game.EndTime()) > m.gameRetention {
+					ctx, span := tracing.Start(context.Background(), "game.archive", tracing.GameID.String(game.ID.String()))
+					m.archive(ctx, game)
+					span.End()
+				}
+			}
+		}
+	}
+}
+
+// archive records the archive of a game which is over, removes the game
+// from the live games, stops its bots and disconnects it.
+func (m *Manager) archive(ctx context.Context, game *games.Game) {
+	gameID := game.ID.String()
+
+	err := m.storage(ctx).ArchiveGame(game.Archive())
+	if err != nil {
+		m.log.Error().Str(logging.GameID, game.ID.String()).Msgf("unable to archive game: %s", err.Error())
+		return
+	}
+
+	err = m.storage(ctx).RemoveGame(gameID)
+	if err != nil {
+		m.log.Error().Str(logging.GameID, game.ID.String()).Msgf("unable to remove game: %s", err.Error())
+		return
+	}
+
+	m.releaseBots(ctx, game)
+	m.releaseTakeovers(game)
+
+	game.Close()
+
+	m.log.Info().Str(logging.GameID, game.ID.String()).Msg("game archived")
+	m.publish(ctx, []byte(`{"type": "archived", "emitter": "manager", "id": "`+gameID+`", "data": ""}`))
+}
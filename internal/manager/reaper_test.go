@@ -0,0 +1,51 @@
+package manager_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games/dice"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+)
+
+func TestReaper(t *testing.T) {
+	game, ids := newLeaveGame(t, dice.Type, "reaped1", "reaped2")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+
+	response := call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	response = call(t, rpc(manager.LeaveGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+ids[1]+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while leaving game: %s", response.Result)
+	}
+
+	// finished games are live for the retention time
+	_, err := store.GameByID(game.ID.String())
+	if err != nil {
+		t.Fatalf("expected finished game to be kept: %s", err.Error())
+	}
+
+	waitFor(t, "game archived", func() bool {
+		_, err := store.GameByID(game.ID.String())
+		return err != nil
+	})
+
+	archive, err := store.ArchivedGame(game.ID.String())
+	if err != nil {
+		t.Fatalf("unexpected error while retrieving archive: %s", err.Error())
+	}
+	if archive.Aborted || len(archive.Ranking) != 2 || archive.Ranking[0][0] != ids[0] {
+		t.Errorf("expected finished game won by %s to be archived, got %+v", ids[0], archive)
+	}
+
+	// the log of archived games is still available, with their seed
+	response = call(t, rpc(manager.GameLog), `{"id": "`+game.ID.String()+`"}`)
+	if response.Status != "ok" || !strings.Contains(response.Result, ids[1]) || !strings.Contains(response.Result, "[Seed") {
+		t.Errorf("expected log of archived game, got %q", response.Result)
+	}
+}
@@ -0,0 +1,155 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"runtime/debug"
+	"sync"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/go-playground/validator/v10"
+)
+
+// Handler handles the data of a RPC, and replies with the callback.
+type Handler func(ctx context.Context, data []byte, c centrifuge.RPCCallback)
+
+// Middleware wraps the handler of a RPC method, to apply a policy to all
+// the methods.
+type Middleware func(method string, next Handler) Handler
+
+// WithMiddleware wraps the handlers of the RPC methods with middleware, in
+// the given order: the first middleware is the outermost. They run after
+// the middleware of the manager, which trace, count, log the RPC and
+// recover from their panics.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(m *Manager) {
+		m.middleware = append(m.middleware, middleware...)
+	}
+}
+
+// Register registers the handler of a RPC method, wrapped with the
+// middleware of the manager. It replaces the handler registered before
+// for the method, if any.
+func (m *Manager) Register(method string, h Handler) {
+	h = m.chain(method, h)
+
+	m.rpcMu.Lock()
+	m.handlers[method] = h
+	m.rpcMu.Unlock()
+}
+
+// chain wraps the handler of a RPC method with the middleware of the manager.
+func (m *Manager) chain(method string, h Handler) Handler {
+	middleware := append([]Middleware{traceRPC, m.metrics.observe, m.logRPC, m.recoverRPC}, m.middleware...)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](method, h)
+	}
+
+	return h
+}
+
+// tokenField matches the token field of the data of a RPC, eg the admin token.
+var tokenField = regexp.MustCompile(`"token"\s*:\s*"(?:[^"\\]|\\.)*"`)
+
+// redact returns the data of a RPC to log, with its token hidden.
+func redact(data []byte) string {
+	return tokenField.ReplaceAllString(string(data), `"token": "***"`)
+}
+
+// logRPC logs the RPC, without its token.
+func (m *Manager) logRPC(method string, next Handler) Handler {
+	return func(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+		m.log.Info().Msgf("client RPC: %s %s", method, redact(data))
+		next(ctx, data, c)
+	}
+}
+
+// recoverRPC replies with an error to the RPC whose handler panics, unless
+// it already replied.
+func (m *Manager) recoverRPC(method string, next Handler) Handler {
+	return func(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+		var once sync.Once
+		reply := func(r centrifuge.RPCReply, err error) {
+			once.Do(func() { c(r, err) })
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				m.log.Error().Msgf("RPC %s panics: %v\n%s", method, r, string(debug.Stack()))
+				msg := fmt.Sprintf("internal error handling %s", method)
+				reply(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, KO, msg))}, nil)
+			}
+		}()
+
+		next(ctx, data, reply)
+	}
+}
+
+var validate = validator.New()
+
+// Typed returns the handler of a RPC with a typed request and response. The
+// request is unmarshaled from the data, empty data being the zero request,
+// and validated against its validate tags. The response is marshaled as the
+// result of the reply: an object or an array is the JSON document in a
+// string, a string response is the result as is, and any other value, eg
+// a bool, is the raw JSON value. The error is the result of a ko reply.
+func Typed[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error)) Handler {
+	return func(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+		var status, msg string
+		var b []byte
+		var err error
+
+		var req Req
+		if len(data) > 0 {
+			err = json.Unmarshal(data, &req)
+		}
+		if err != nil {
+			status = KO
+			msg = fmt.Sprintf("unable to unmarshal data %q: %s", string(data), err.Error())
+			c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+			return
+		}
+
+		err = validate.Struct(req)
+		if err != nil {
+			status = KO
+			msg = fmt.Sprintf("invalid data %q: %s", string(data), err.Error())
+			c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+			return
+		}
+
+		resp, err := fn(ctx, req)
+		if err != nil {
+			status = KO
+			msg = err.Error()
+			c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+			return
+		}
+
+		if s, ok := any(resp).(string); ok {
+			status = OK
+			msg = s
+			c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+			return
+		}
+
+		b, err = json.Marshal(resp)
+		if err != nil {
+			status = KO
+			msg = fmt.Sprintf("unable to marshal response: %s", err.Error())
+			c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+			return
+		}
+
+		status = OK
+		if b[0] != '{' && b[0] != '[' {
+			c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %s}`, status, b))}, nil)
+			return
+		}
+
+		msg = string(b)
+		c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "result": %q}`, status, msg))}, nil)
+	}
+}
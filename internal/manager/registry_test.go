@@ -0,0 +1,108 @@
+package manager_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/centrifugal/centrifuge"
+	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/storage/memory"
+)
+
+type EchoData struct {
+	Message string `json:"message" validate:"required"`
+}
+
+func TestRegistry(t *testing.T) {
+	log := newLogger()
+	calls := []string{}
+	audit := func(method string, next manager.Handler) manager.Handler {
+		return func(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+			calls = append(calls, method)
+			next(ctx, data, c)
+		}
+	}
+	m := manager.New(&log, memory.New(&log), manager.WithMiddleware(audit))
+
+	m.Register("echo", manager.Typed(func(ctx context.Context, req EchoData) (EchoData, error) {
+		if req.Message == "fail" {
+			return EchoData{}, fmt.Errorf("echo failed")
+		}
+		return req, nil
+	}))
+	m.Register("text", manager.Typed(func(ctx context.Context, req manager.Empty) (string, error) {
+		return "plain text", nil
+	}))
+	m.Register("panic", func(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+		panic("boom")
+	})
+
+	// handle calls a method of the test manager, which must reply once.
+	handle := func(method, data string) Response {
+		var response Response
+
+		replies := 0
+		m.HandleRPC(context.Background(), centrifuge.RPCEvent{Method: method, Data: []byte(data)}, func(r centrifuge.RPCReply, e error) {
+			replies++
+			err := json.Unmarshal(r.Data, &response)
+			if err != nil {
+				t.Fatalf("error while unmarshaling response %q: %s", string(r.Data), err.Error())
+			}
+		})
+		if replies != 1 {
+			t.Fatalf("expected a single reply to %s, got %d", method, replies)
+		}
+
+		return response
+	}
+
+	response := handle("echo", `{"message": "hello"}`)
+	if response.Status != "ok" || response.Result != `{"message":"hello"}` {
+		t.Errorf("expected echo of the message, got %+v", response)
+	}
+
+	for _, data := range []string{`{"message": `, `{}`, `{"message": "fail"}`} {
+		response = handle("echo", data)
+		if response.Status != "ko" {
+			t.Errorf("expected echo of %s to fail, got %+v", data, response)
+		}
+	}
+
+	// empty data is the zero request, and strings are the result as is
+	response = handle("text", ``)
+	if response.Status != "ok" || response.Result != "plain text" {
+		t.Errorf("expected plain text, got %+v", response)
+	}
+
+	response = handle("panic", `{}`)
+	if response.Status != "ko" || !strings.Contains(response.Result, "internal error") {
+		t.Errorf("expected panic to be recovered with an error, got %+v", response)
+	}
+
+	// registered methods, the built-in ones included, go through the middleware
+	handle(manager.ListGames, `{}`)
+	handle("unknown", `{}`)
+	handle("other", `{}`)
+	expected := "echo,echo,echo,echo,text,panic,listGames,unsupported,unsupported"
+	if strings.Join(calls, ",") != expected {
+		t.Errorf("expected middleware calls %s, got %v", expected, calls)
+	}
+}
+
+func TestRedactedToken(t *testing.T) {
+	var buf bytes.Buffer
+	log := zerolog.New(&buf)
+	m := manager.New(&log, memory.New(&log), manager.WithAdminToken("secret"))
+
+	m.HandleRPC(context.Background(), centrifuge.RPCEvent{Method: manager.ListGames, Data: []byte(`{"token": "secret"}`)}, func(r centrifuge.RPCReply, e error) {})
+
+	if strings.Contains(buf.String(), "secret") || !strings.Contains(buf.String(), `\"token\": \"***\"`) {
+		t.Errorf("expected the token to be redacted from the logs, got %s", buf.String())
+	}
+}
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/centrifugal/centrifuge"
@@ -17,6 +18,21 @@ const (
 	IsGameStarted    string = "isGameStarted"
 	JoinGame         string = "joinGame"
 	PlayerInit       string = "playerInit"
+	PlayMove         string = "playMove"
+	GameLog          string = "gameLog"
+	AddBot           string = "addBot"
+	SuggestMove      string = "suggestMove"
+	GameState        string = "gameState"
+	KickPlayer       string = "kickPlayer"
+	SetGameOptions   string = "setGameOptions"
+	LeaveGame        string = "leaveGame"
+	JoinGameByCode   string = "joinGameByCode"
+	RotateInviteCode string = "rotateInviteCode"
+	EnterQueue       string = "enterQueue"
+	LeaveQueue       string = "leaveQueue"
+	ConfirmMatch     string = "confirmMatch"
+	PlayerProfile    string = "playerProfile"
+	SetReady         string = "setReady"
 )
 
 const (
@@ -25,36 +41,69 @@ const (
 	EmptyJSON string = "{}"
 )
 
-// HandleRPC execute remote procedure call defined by the RPCEvent, then call the provided callback.
-func (m *Manager) HandleRPC(e centrifuge.RPCEvent, c centrifuge.RPCCallback) {
-	m.log.Info().Msgf("client RPC: %s %s", e.Method, string(e.Data))
+// Unsupported is the method under which the calls of unsupported methods
+// are logged, counted and traced.
+const Unsupported string = "unsupported"
+
+// Empty is the request or the response of the RPC methods without data.
+type Empty struct{}
+
+// registerRPC registers the handlers of the RPC methods.
+func (m *Manager) registerRPC() {
 	// Players related rpc
-	switch e.Method {
-	case RegisterPlayer:
-		m.RegisterPlayer(e.Data, c)
-	case UnregisterPlayer:
-		m.UnregisterPlayer(e.Data, c)
-	case ListPlayers:
-		m.ListPlayers(e.Data, c)
+	m.Register(RegisterPlayer, Typed(m.RegisterPlayer))
+	m.Register(UnregisterPlayer, Typed(m.UnregisterPlayer))
+	m.Register(ListPlayers, Typed(m.ListPlayers))
+	m.Register(PlayerProfile, Typed(m.PlayerProfile))
 	// Games related rpc
-	case ListGames:
-		m.ListGames(e.Data, c)
-	case CreateGame:
-		m.CreateGame(e.Data, c)
-	case StartGame:
-		m.StartGame(e.Data, c)
-	case StopGame:
-		m.StopGame(e.Data, c)
-	case IsGameStarted:
-		m.IsGameStarted(e.Data, c)
-	case JoinGame:
-		m.JoinGame(e.Data, c)
-	case PlayerInit:
-		m.PlayerInit(e.Data, c)
-	// Default
-	default:
-		msg := fmt.Sprintf("unsupported method %s", e.Method)
-		m.log.Error().Msg(msg)
-		c(centrifuge.RPCReply{Data: []byte(`{"status": "ko", "reason":"` + msg + `"}`)}, nil)
+	m.Register(ListGames, Typed(m.ListGames))
+	m.Register(CreateGame, Typed(m.CreateGame))
+	m.Register(StartGame, Typed(m.StartGame))
+	m.Register(StopGame, Typed(m.StopGame))
+	m.Register(IsGameStarted, Typed(m.IsGameStarted))
+	m.Register(JoinGame, Typed(m.JoinGame))
+	m.Register(PlayerInit, Typed(m.PlayerInit))
+	m.Register(PlayMove, Typed(m.PlayMove))
+	m.Register(GameLog, Typed(m.GameLog))
+	m.Register(AddBot, Typed(m.AddBot))
+	m.Register(SuggestMove, Typed(m.SuggestMove))
+	m.Register(GameState, Typed(m.GameState))
+	m.Register(KickPlayer, Typed(m.KickPlayer))
+	m.Register(SetGameOptions, Typed(m.SetGameOptions))
+	m.Register(LeaveGame, Typed(m.LeaveGame))
+	m.Register(JoinGameByCode, Typed(m.JoinGameByCode))
+	m.Register(RotateInviteCode, Typed(m.RotateInviteCode))
+	m.Register(SetReady, Typed(m.SetReady))
+	// Matchmaking related rpc
+	m.Register(EnterQueue, Typed(m.EnterQueue))
+	m.Register(LeaveQueue, Typed(m.LeaveQueue))
+	m.Register(ConfirmMatch, Typed(m.ConfirmMatch))
+
+	// unsupported methods go through the middleware under a single name
+	m.fallback = m.chain(Unsupported, m.unsupported)
+}
+
+// methodKey is the context key of the method of an unsupported RPC.
+type methodKey struct{}
+
+// HandleRPC execute remote procedure call defined by the RPCEvent, then call the provided callback.
+func (m *Manager) HandleRPC(ctx context.Context, e centrifuge.RPCEvent, c centrifuge.RPCCallback) {
+	m.rpcMu.RLock()
+	h, ok := m.handlers[e.Method]
+	m.rpcMu.RUnlock()
+
+	if !ok {
+		h = m.fallback
+		ctx = context.WithValue(ctx, methodKey{}, e.Method)
 	}
+
+	h(ctx, e.Data, c)
+}
+
+// unsupported replies with an error to the call of an unsupported method.
+func (m *Manager) unsupported(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+	method, _ := ctx.Value(methodKey{}).(string)
+	msg := fmt.Sprintf("unsupported method %s", method)
+	m.log.Error().Msg(msg)
+	c(centrifuge.RPCReply{Data: []byte(fmt.Sprintf(`{"status": %q, "reason": %q}`, KO, msg))}, nil)
 }
@@ -0,0 +1,105 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/games/dice"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+func TestDiceGame(t *testing.T) {
+	response := call(t, rpc(manager.CreateGame), `{"type": "fake", "minPlayers": 2, "maxPlayers": 2}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while creating a game of unknown type")
+	}
+
+	response = call(t, rpc(manager.CreateGame), `{"type": "dice", "minPlayers": 2, "maxPlayers": 2, "options": {"scoreLimit": 15}}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while creating game: %s", response.Result)
+	}
+
+	var game games.Game
+	err := json.Unmarshal([]byte(response.Result), &game)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	if game.Type != dice.Type {
+		t.Errorf("expected game type %s, got %s", dice.Type, game.Type)
+	}
+
+	response = call(t, rpc(manager.AddBot), `{"idGame": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ko" {
+		t.Errorf("expected error while adding a bot to a dice game")
+	}
+
+	ids := []string{}
+	for _, name := range []string{"dice1", "dice2"} {
+		response = call(t, rpc(manager.RegisterPlayer), `{"name": "`+name+`"}`)
+		var player players.Player
+		err = json.Unmarshal([]byte(response.Result), &player)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+player.ID.String()+`", "token": "`+adminToken+`"}`)
+
+		ids = append(ids, player.ID.String())
+		response = call(t, rpc(manager.JoinGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+player.ID.String()+`", "token": "`+adminToken+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while joining game: %s", response.Result)
+		}
+	}
+
+	response = call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	// players hold as soon as possible
+	finished := false
+	for i := 0; i < 1000 && !finished; i++ {
+		for _, id := range ids {
+			response = call(t, rpc(manager.GameState), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+id+`"}`)
+			if response.Status != "ok" {
+				t.Fatalf("unexpected error while getting game state: %s", response.Result)
+			}
+
+			var state struct {
+				Type  string     `json:"type"`
+				State dice.State `json:"state"`
+				Moves []string   `json:"moves"`
+			}
+			err = json.Unmarshal([]byte(response.Result), &state)
+			if err != nil {
+				t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+			}
+
+			if state.State.Winner != "" {
+				finished = true
+				break
+			}
+
+			if len(state.Moves) == 0 {
+				continue
+			}
+
+			move := dice.Roll
+			if utils.ContainsString(state.Moves, dice.Hold) {
+				move = dice.Hold
+			}
+
+			response = call(t, rpc(manager.PlayMove), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+id+`", "move": "`+move+`", "token": "`+adminToken+`"}`)
+			if response.Status != "ok" {
+				t.Fatalf("unexpected error while playing move %s: %s", move, response.Result)
+			}
+		}
+	}
+
+	if !finished {
+		t.Errorf("expected dice game to be finished")
+	}
+}
@@ -0,0 +1,222 @@
+package manager
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/bots"
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// takeover is a bot playing the seat of a player in a game.
+type takeover struct {
+	game *games.Game
+	bot  *bots.Bot
+}
+
+// WithBotTakeover hands the seat of a player to a bot when the player is
+// disconnected from a running game for longer than the grace period, or
+// when the player did not answer the game in time. The seat is handed back
+// to the player when the player reconnects before the end of the game.
+func WithBotTakeover(grace time.Duration) Option {
+	return func(m *Manager) {
+		m.takeoverEnabled = true
+		m.takeoverGrace = grace
+	}
+}
+
+// handleGameEvent returns the handler of the events published by a game.
+func (m *Manager) handleGameEvent(game *games.Game) games.EventHandler {
+	return func(e games.Event) {
+		ctx, span := tracing.Start(context.Background(), "game."+e.Type, tracing.GameID.String(game.ID.String()))
+		if e.Player != "" {
+			span.SetAttributes(tracing.PlayerID.String(e.Player))
+		}
+		defer span.End()
+
+		m.record(e)
+		if e.Type == "timeout" {
+			m.metrics.timeouts.WithLabelValues(e.Data).Inc()
+		}
+		if e.Type == "timeout" && m.takeoverEnabled {
+			go m.takeover(game, e.Player)
+		}
+		if e.Type == "end" {
+			go func() {
+				m.rate(ctx, game)
+				m.releaseBots(ctx, game)
+				m.releaseTakeovers(game)
+			}()
+		}
+	}
+}
+
+// playerDisconnected schedules the takeover of the seats of a disconnected player.
+func (m *Manager) playerDisconnected(pID string) {
+	if !m.takeoverEnabled {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timer, ok := m.takeoverTimers[pID]
+	if ok {
+		timer.Stop()
+	}
+
+	m.takeoverTimers[pID] = time.AfterFunc(m.takeoverGrace, func() {
+		m.mu.Lock()
+		delete(m.takeoverTimers, pID)
+		m.mu.Unlock()
+
+		for _, game := range m.store.ListGames() {
+			if utils.ContainsString(game.Players(), pID) && game.IsStarted() && !game.IsFinished() && !game.HasForfeited(pID) {
+				m.takeover(game, pID)
+			}
+		}
+	})
+}
+
+// playerConnected cancels the scheduled takeover of a reconnected player's seats,
+// and hands back the seats already taken over, but the seats of the games
+// the player forfeited.
+func (m *Manager) playerConnected(pID string) {
+	m.mu.Lock()
+	timer, ok := m.takeoverTimers[pID]
+	if ok {
+		timer.Stop()
+		delete(m.takeoverTimers, pID)
+	}
+	takeovers := []takeover{}
+	forfeits := []takeover{}
+	for _, t := range m.takeovers[pID] {
+		if t.game.HasForfeited(pID) {
+			forfeits = append(forfeits, t)
+			continue
+		}
+		takeovers = append(takeovers, t)
+	}
+	delete(m.takeovers, pID)
+	if len(forfeits) > 0 {
+		m.takeovers[pID] = forfeits
+	}
+	m.mu.Unlock()
+
+	for _, t := range takeovers {
+		t.bot.Stop()
+		if t.game.IsFinished() {
+			continue
+		}
+
+		m.log.Info().Str(logging.GameID, t.game.ID.String()).Str(logging.PlayerID, pID).Msg("hand seat back to player")
+		err := t.game.SetBotControlled(pID, false)
+		if err != nil {
+			m.log.Error().Msgf("error handing seat back: %s", err.Error())
+		}
+	}
+}
+
+// takeover hands the seat of a player in a game to a greedy bot. Seats of
+// games bots cannot play are not taken over.
+func (m *Manager) takeover(game *games.Game, pID string) {
+	if bots.Supports(game) != nil {
+		return
+	}
+
+	strategy, err := bots.NewStrategy(bots.GreedyStrategy, rand.New(rand.NewSource(time.Now().UTC().UnixNano())))
+	if err != nil {
+		m.log.Error().Msgf("error creating takeover bot: %s", err.Error())
+		return
+	}
+
+	bot := bots.New(m.root, pID, game, strategy)
+
+	// the takeover is recorded under the lock, and the seat handed over
+	// without it, as the game publishes a seat event
+	m.mu.Lock()
+	for _, t := range m.takeovers[pID] {
+		if t.game == game {
+			m.mu.Unlock()
+			return
+		}
+	}
+	m.takeovers[pID] = append(m.takeovers[pID], takeover{game: game, bot: bot})
+	m.mu.Unlock()
+
+	m.log.Info().Str(logging.GameID, game.ID.String()).Str(logging.PlayerID, pID).Msg("hand seat to a bot")
+	err = game.SetBotControlled(pID, true)
+	if err != nil {
+		m.log.Error().Msgf("error taking seat over: %s", err.Error())
+		m.dropTakeover(game, pID)
+		return
+	}
+
+	bot.Start()
+}
+
+// dropTakeover forgets the takeover of the seat of a player in a game.
+func (m *Manager) dropTakeover(game *games.Game, pID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := []takeover{}
+	for _, t := range m.takeovers[pID] {
+		if t.game != game {
+			kept = append(kept, t)
+		}
+	}
+	delete(m.takeovers, pID)
+	if len(kept) > 0 {
+		m.takeovers[pID] = kept
+	}
+}
+
+// releaseTakeovers stops the bots playing the seats taken over in a game, and
+// cancels the scheduled takeovers of its players who are not seated in
+// another running game, once the game ended or is archived.
+func (m *Manager) releaseTakeovers(game *games.Game) {
+	idle := []string{}
+	for _, pID := range game.Players() {
+		running := false
+		for _, g := range m.store.ListGames() {
+			if g != game && utils.ContainsString(g.Players(), pID) && g.IsStarted() && !g.IsFinished() {
+				running = true
+				break
+			}
+		}
+		if !running {
+			idle = append(idle, pID)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, pID := range idle {
+		timer, ok := m.takeoverTimers[pID]
+		if ok {
+			timer.Stop()
+			delete(m.takeoverTimers, pID)
+		}
+	}
+
+	for pID, takeovers := range m.takeovers {
+		kept := []takeover{}
+		for _, t := range takeovers {
+			if t.game == game {
+				t.bot.Stop()
+				continue
+			}
+			kept = append(kept, t)
+		}
+		delete(m.takeovers, pID)
+		if len(kept) > 0 {
+			m.takeovers[pID] = kept
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package manager_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	centrifuge "github.com/centrifugal/centrifuge-go"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// connect connects a client subscribed to a game topic on behalf of a player.
+func connect(t *testing.T, topic, pID string) *centrifuge.Client {
+	var wg sync.WaitGroup
+
+	log := newLogger()
+	c := utils.NewClient(&log, utils.DefaultWebsocketURL, &wg)
+	wg.Add(1)
+	err := c.Connect()
+	if err != nil {
+		t.Fatalf("connect error: %s", err.Error())
+	}
+	wg.Wait()
+
+	_, err = utils.Subscribe(&log, c, topic,
+		utils.WithSubscriptionConfig(centrifuge.SubscriptionConfig{
+			Data: []byte(`{"id":"` + pID + `"}`),
+		}),
+	)
+	if err != nil {
+		t.Fatalf("subscribe error: %s", err.Error())
+	}
+
+	return c
+}
+
+// waitFor polls a condition until it is true or a timeout occurs.
+func waitFor(t *testing.T, what string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestTakeover(t *testing.T) {
+	response := call(t, rpc(manager.CreateGame), `{"minPlayers": 2, "maxPlayers": 2}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while creating game: %s", response.Result)
+	}
+
+	var g games.Game
+	err := json.Unmarshal([]byte(response.Result), &g)
+	if err != nil {
+		t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+	}
+
+	game, err := store.GameByID(g.ID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	ids := []string{}
+	clients := []*centrifuge.Client{}
+	for _, name := range []string{"takeover1", "takeover2"} {
+		response = call(t, rpc(manager.RegisterPlayer), `{"name": "`+name+`"}`)
+		var player players.Player
+		err = json.Unmarshal([]byte(response.Result), &player)
+		if err != nil {
+			t.Fatalf("error while unmarshaling result %q: %s", response.Result, err.Error())
+		}
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+player.ID.String()+`", "token": "`+adminToken+`"}`)
+
+		id := player.ID.String()
+		ids = append(ids, id)
+		response = call(t, rpc(manager.JoinGame), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+id+`", "token": "`+adminToken+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while joining game: %s", response.Result)
+		}
+
+		c := connect(t, game.TopicName, id)
+		defer c.Close()
+		clients = append(clients, c)
+	}
+
+	response = call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if response.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", response.Result)
+	}
+
+	for _, id := range ids {
+		response = call(t, rpc(manager.PlayMove), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+id+`", "move": "Ra1 Rb1", "token": "`+adminToken+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while revealing cards: %s", response.Result)
+		}
+		response = call(t, rpc(manager.PlayerInit), `{"idGame": "`+game.ID.String()+`", "idPlayer": "`+id+`"}`)
+		if response.Status != "ok" {
+			t.Fatalf("unexpected error while initializing player: %s", response.Result)
+		}
+	}
+
+	if game.IsBotControlled(ids[0]) {
+		t.Errorf("expected seat to be controlled by the player")
+	}
+
+	// the first player disconnects, a bot takes its seat over after the grace period
+	clients[0].Close()
+	waitFor(t, "bot takeover", func() bool { return game.IsBotControlled(ids[0]) })
+
+	// the bot plays the first player's turn
+	waitFor(t, "bot turn", func() bool { return game.CurrentPlayer() == ids[1] })
+
+	// the first player reconnects, the seat is handed back
+	c := connect(t, game.TopicName, ids[0])
+	defer c.Close()
+	waitFor(t, "seat hand back", func() bool { return !game.IsBotControlled(ids[0]) })
+}
@@ -0,0 +1,81 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/centrifugal/centrifuge"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/storage"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+)
+
+// storage returns the storage of the manager, recording its operations as
+// children of the span of a context.
+func (m *Manager) storage(ctx context.Context) storage.Storage {
+	return storage.Traced(ctx, m.store)
+}
+
+// playerMethods are the RPC whose id identifies a player rather than a game.
+var playerMethods = map[string]bool{
+	UnregisterPlayer: true,
+	PlayerProfile:    true,
+}
+
+// traceRPC is the middleware tracing the RPC.
+func traceRPC(method string, next Handler) Handler {
+	return func(ctx context.Context, data []byte, c centrifuge.RPCCallback) {
+		ctx, c = startRPC(ctx, method, data, c)
+		next(ctx, data, c)
+	}
+}
+
+// startRPC starts the span of a RPC, with the game and the player it is
+// about, and wraps its callback so that the span ends with the status of
+// the reply.
+func startRPC(ctx context.Context, method string, data []byte, c centrifuge.RPCCallback) (context.Context, centrifuge.RPCCallback) {
+	ctx, span := tracing.Start(ctx, "rpc."+method, tracing.Method.String(method))
+
+	var ids struct {
+		ID       string `json:"id"`
+		IDGame   string `json:"idGame"`
+		IDPlayer string `json:"idPlayer"`
+	}
+	if json.Unmarshal(data, &ids) == nil {
+		if ids.ID != "" && playerMethods[method] {
+			ids.IDPlayer = ids.ID
+		} else if ids.ID != "" {
+			ids.IDGame = ids.ID
+		}
+		if ids.IDGame != "" {
+			span.SetAttributes(tracing.GameID.String(ids.IDGame))
+		}
+		if ids.IDPlayer != "" {
+			span.SetAttributes(tracing.PlayerID.String(ids.IDPlayer))
+		}
+	}
+
+	return ctx, func(reply centrifuge.RPCReply, err error) {
+		endRPC(span, reply, err)
+		c(reply, err)
+	}
+}
+
+// endRPC ends the span of a RPC with the status of its reply.
+func endRPC(span trace.Span, reply centrifuge.RPCReply, err error) {
+	var response struct {
+		Status string `json:"status"`
+		Result string `json:"result"`
+	}
+	if err != nil || json.Unmarshal(reply.Data, &response) != nil || response.Status == "" {
+		response.Status = KO
+	}
+
+	span.SetAttributes(tracing.Status.String(response.Status))
+	if response.Status != OK {
+		span.SetStatus(codes.Error, response.Result)
+	}
+	span.End()
+}
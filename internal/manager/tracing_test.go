@@ -0,0 +1,72 @@
+package manager_test
+
+import (
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games/dice"
+	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
+)
+
+// attribute returns the value of an attribute of a span.
+func attribute(span sdktrace.ReadOnlySpan, key string) string {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracing(t *testing.T) {
+	game, ids := newLeaveGame(t, dice.Type, "traced1", "traced2")
+	for _, id := range ids {
+		defer call(t, rpc(manager.UnregisterPlayer), `{"id": "`+id+`", "token": "`+adminToken+`"}`)
+	}
+	c := connect(t, utils.ServerPublishChannel, ids[0])
+	defer c.Close()
+
+	reply := call(t, rpc(manager.StartGame), `{"id": "`+game.ID.String()+`", "token": "`+adminToken+`"}`)
+	if reply.Status != "ok" {
+		t.Fatalf("unexpected error while starting game: %s", reply.Result)
+	}
+
+	// the root span of the RPC
+	var root sdktrace.ReadOnlySpan
+	for _, span := range spans.Ended() {
+		if span.Name() == "rpc."+manager.StartGame && attribute(span, string(tracing.GameID)) == game.ID.String() {
+			root = span
+		}
+	}
+	if root == nil {
+		t.Fatalf("expected a span of the RPC starting game %s", game.ID.String())
+	}
+	if attribute(root, string(tracing.Status)) != "ok" {
+		t.Errorf("expected RPC span with ok status, got %v", root.Attributes())
+	}
+
+	// the storage operations, publications and messages of the RPC
+	children := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans.Ended() {
+		if span.SpanContext().TraceID() == root.SpanContext().TraceID() && span.Parent().SpanID() == root.SpanContext().SpanID() {
+			children[span.Name()] = span
+		}
+	}
+
+	for _, name := range []string{"storage.GameByID", "storage.StartGame", "publish", "send"} {
+		span, ok := children[name]
+		if !ok {
+			t.Errorf("expected a %s span in the trace of the RPC, got %v", name, children)
+			continue
+		}
+		if name != "send" && attribute(span, string(tracing.GameID)) != game.ID.String() {
+			t.Errorf("expected %s span of game %s, got %v", name, game.ID.String(), span.Attributes())
+		}
+	}
+	if send, ok := children["send"]; ok && attribute(send, string(tracing.PlayerID)) != ids[0] {
+		t.Errorf("expected message sent to connected player %s, got %v", ids[0], send.Attributes())
+	}
+}
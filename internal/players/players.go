@@ -6,14 +6,16 @@ import (
 
 // Player represents a game player.
 type Player struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Score int       `json:"score"`
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Rating float64   `json:"rating"`
+	Games  int       `json:"games"`
 }
 
 func New(name string) *Player {
 	return &Player{
-		ID:   uuid.New(),
-		Name: name,
+		ID:     uuid.New(),
+		Name:   name,
+		Rating: InitialRating,
 	}
 }
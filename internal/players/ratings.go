@@ -0,0 +1,56 @@
+package players
+
+import (
+	"math"
+)
+
+const (
+	// InitialRating is the rating of new players.
+	InitialRating float64 = 1500
+	// RatingK is the largest rating change of a player after a game.
+	RatingK float64 = 32
+)
+
+// Rate returns the new ratings of the players of a finished game, ranked
+// from best to worst, tied players being grouped. Ratings are updated with
+// a multiplayer Elo: each player wins, draws or loses against every other
+// player according to their ranks, and its rating change is shared out
+// among its opponents, so that a game weighs the same whatever the table size.
+func Rate(ratings map[string]float64, ranking [][]string) map[string]float64 {
+	ranks := make(map[string]int)
+	for rank, group := range ranking {
+		for _, pID := range group {
+			ranks[pID] = rank
+		}
+	}
+
+	result := make(map[string]float64)
+	opponents := float64(len(ranks) - 1)
+	for pID, rank := range ranks {
+		result[pID] = ratings[pID]
+		if opponents == 0 {
+			continue
+		}
+
+		change := 0.0
+		for opponent, opponentRank := range ranks {
+			if opponent == pID {
+				continue
+			}
+
+			actual := 0.5
+			switch {
+			case rank < opponentRank:
+				actual = 1
+			case rank > opponentRank:
+				actual = 0
+			}
+			expected := 1 / (1 + math.Pow(10, (ratings[opponent]-ratings[pID])/400))
+			change += actual - expected
+		}
+
+		result[pID] += RatingK * change / opponents
+	}
+
+	return result
+}
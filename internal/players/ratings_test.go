@@ -0,0 +1,52 @@
+package players_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+)
+
+func TestRate(t *testing.T) {
+	initial := players.InitialRating
+
+	// players of the same strength
+	ratings := players.Rate(map[string]float64{"p1": initial, "p2": initial}, [][]string{{"p1"}, {"p2"}})
+	if ratings["p1"] != initial+players.RatingK/2 || ratings["p2"] != initial-players.RatingK/2 {
+		t.Errorf("expected winner to gain and loser to lose %f, got %v", players.RatingK/2, ratings)
+	}
+
+	ratings = players.Rate(map[string]float64{"p1": initial, "p2": initial}, [][]string{{"p1", "p2"}})
+	if ratings["p1"] != initial || ratings["p2"] != initial {
+		t.Errorf("expected tie to leave ratings unchanged, got %v", ratings)
+	}
+
+	// beating a stronger player gains more than beating a weaker one
+	ratings = players.Rate(map[string]float64{"p1": 1400, "p2": 1600}, [][]string{{"p1"}, {"p2"}})
+	upset := ratings["p1"] - 1400
+	ratings = players.Rate(map[string]float64{"p1": 1600, "p2": 1400}, [][]string{{"p1"}, {"p2"}})
+	expected := ratings["p1"] - 1600
+	if upset <= expected {
+		t.Errorf("expected upset to gain more (%f) than expected win (%f)", upset, expected)
+	}
+
+	// rating points are neither created nor lost
+	before := map[string]float64{"p1": 1450, "p2": 1500, "p3": 1720, "p4": 1500}
+	ratings = players.Rate(before, [][]string{{"p2"}, {"p1", "p4"}, {"p3"}})
+	sum := 0.0
+	for pID, rating := range ratings {
+		sum += rating - before[pID]
+	}
+	if math.Abs(sum) > 1e-9 {
+		t.Errorf("expected ratings sum to be unchanged, got a change of %f", sum)
+	}
+	if ratings["p2"] <= before["p2"] || ratings["p3"] >= before["p3"] {
+		t.Errorf("expected best player to gain and worst player to lose, got %v", ratings)
+	}
+
+	// a single player is not rated
+	ratings = players.Rate(map[string]float64{"p1": initial}, [][]string{{"p1"}})
+	if ratings["p1"] != initial {
+		t.Errorf("expected single player rating to be unchanged, got %v", ratings)
+	}
+}
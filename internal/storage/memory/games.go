@@ -10,6 +10,9 @@ import (
 
 // ListGames returns all games.
 func (m *Memory) ListGames() []*games.Game {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	result := []*games.Game{}
 	for _, value := range m.games {
 		result = append(result, value)
@@ -18,14 +21,16 @@ func (m *Memory) ListGames() []*games.Game {
 }
 
 // CreateGame instantiates a new game.
-func (m *Memory) CreateGame(min, max int) (*games.Game, error) {
-	game := games.New(m.log, min, max)
+func (m *Memory) CreateGame(min, max int, opts ...games.Option) (*games.Game, error) {
+	game := games.New(m.root, min, max, opts...)
 
 	err := game.Connect()
 	if err != nil {
 		return nil, fmt.Errorf("error creating game: %s", err.Error())
 	}
+	m.mu.Lock()
 	m.games[game.ID.String()] = game
+	m.mu.Unlock()
 
 	return game, nil
 }
@@ -36,7 +41,9 @@ func (m *Memory) StartGame(id string) error {
 		return fmt.Errorf("nil game id")
 	}
 
+	m.mu.RLock()
 	game, ok := m.games[id]
+	m.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("unknown game id %s", id)
 	}
@@ -45,8 +52,6 @@ func (m *Memory) StartGame(id string) error {
 	if err != nil {
 		return fmt.Errorf("error starting game: %s", err.Error())
 	}
-	m.games[id] = game
-
 	return nil
 }
 
@@ -56,7 +61,9 @@ func (m *Memory) StopGame(id string) error {
 		return fmt.Errorf("nil game id")
 	}
 
+	m.mu.RLock()
 	game, ok := m.games[id]
+	m.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("unknown game id %s", id)
 	}
@@ -66,8 +73,6 @@ func (m *Memory) StopGame(id string) error {
 		return fmt.Errorf("error stopping game: %s", err.Error())
 	}
 
-	m.games[id] = game
-
 	return nil
 }
 
@@ -77,7 +82,9 @@ func (m *Memory) IsGameStarted(id string) (bool, error) {
 		return false, fmt.Errorf("nil game id")
 	}
 
+	m.mu.RLock()
 	game, ok := m.games[id]
+	m.mu.RUnlock()
 	if !ok {
 		return false, fmt.Errorf("unknown game id %s", id)
 	}
@@ -91,7 +98,9 @@ func (m *Memory) JoinGame(idGame, idPlayer string) error {
 		return fmt.Errorf("nil game id")
 	}
 
+	m.mu.RLock()
 	game, ok := m.games[idGame]
+	m.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("unknown game id %s", idGame)
 	}
@@ -100,7 +109,9 @@ func (m *Memory) JoinGame(idGame, idPlayer string) error {
 		return fmt.Errorf("nil player id")
 	}
 
+	m.mu.RLock()
 	_, ok = m.players[idPlayer]
+	m.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("unknown player id %s", idPlayer)
 	}
@@ -109,13 +120,15 @@ func (m *Memory) JoinGame(idGame, idPlayer string) error {
 	if err != nil {
 		return fmt.Errorf("error adding player to game: %s", err.Error())
 	}
-	m.games[idGame] = game
 
 	return nil
 }
 
 // GameByID returns a game object from its ID.
 func (m *Memory) GameByID(id string) (*games.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	// if provided id matches an existing game
 	if id != uuid.Nil.String() {
 		game, ok := m.games[id]
@@ -126,3 +139,60 @@ func (m *Memory) GameByID(id string) (*games.Game, error) {
 
 	return nil, fmt.Errorf("unknown game id: %s", id)
 }
+
+// GameByCode returns a private game object from its invite code.
+func (m *Memory) GameByCode(code string) (*games.Game, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if code != "" {
+		for _, game := range m.games {
+			if game.InviteCode() == code {
+				return game, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unknown invite code: %s", code)
+}
+
+// RemoveGame removes the game with a given ID.
+func (m *Memory) RemoveGame(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.games[id]
+	if !ok {
+		return fmt.Errorf("unknown game id %s", id)
+	}
+	delete(m.games, id)
+
+	return nil
+}
+
+// ArchiveGame records the archive of a game which is over.
+func (m *Memory) ArchiveGame(archive games.Archive) error {
+	if archive.ID == "" || archive.ID == uuid.Nil.String() {
+		return fmt.Errorf("nil game id")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.archives[archive.ID] = archive
+
+	return nil
+}
+
+// ArchivedGame returns the archive of a game from its ID.
+func (m *Memory) ArchivedGame(id string) (games.Archive, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	archive, ok := m.archives[id]
+	if !ok {
+		return games.Archive{}, fmt.Errorf("unknown archived game id: %s", id)
+	}
+
+	return archive, nil
+}
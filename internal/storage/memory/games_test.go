@@ -3,12 +3,16 @@ package memory_test
 import (
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 	"github.com/jtbonhomme/gameserver-websocket/internal/storage/memory"
 )
 
@@ -304,3 +308,149 @@ func TestMemoryJoinGames(t *testing.T) {
 	}
 
 }
+
+func TestMemoryGameByCode(t *testing.T) {
+	logger := zerolog.Nop()
+
+	// concrete memory test storage implementation
+	mem := memory.New(&logger)
+
+	_, err := mem.CreateGame(0, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	private, err := mem.CreateGame(0, 4, games.WithInviteCode("ABCDEF"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	game, err := mem.GameByCode("ABCDEF")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if game.ID != private.ID {
+		t.Errorf("expected game %s, got %s", private.ID.String(), game.ID.String())
+	}
+
+	_, err = mem.GameByCode("")
+	if err == nil {
+		t.Error("expected error when retrieving a game without invite code")
+	}
+
+	_, err = mem.GameByCode("UNKNOWN")
+	if err == nil {
+		t.Error("expected error when retrieving a game with an unknown invite code")
+	}
+}
+
+func TestMemoryRemoveGame(t *testing.T) {
+	logger := zerolog.Nop()
+
+	// concrete memory test storage implementation
+	mem := memory.New(&logger)
+
+	game, err := mem.CreateGame(0, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer game.Close()
+
+	err = mem.RemoveGame(game.ID.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = mem.GameByID(game.ID.String())
+	if err == nil {
+		t.Error("expected error when retrieving a removed game")
+	}
+
+	if len(mem.ListGames()) != 0 {
+		t.Errorf("expected no game listed, got %d", len(mem.ListGames()))
+	}
+
+	err = mem.RemoveGame(game.ID.String())
+	if err == nil {
+		t.Error("expected error when removing a game twice")
+	}
+}
+
+func TestMemoryArchiveGame(t *testing.T) {
+	logger := zerolog.Nop()
+
+	// concrete memory test storage implementation
+	mem := memory.New(&logger)
+
+	err := mem.ArchiveGame(games.Archive{})
+	if err == nil {
+		t.Error("expected error when archiving a game without ID")
+	}
+
+	err = mem.ArchiveGame(games.Archive{ID: "game1", Players: []string{"p1", "p2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	archive, err := mem.ArchivedGame("game1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if archive.ID != "game1" || len(archive.Players) != 2 {
+		t.Errorf("expected archive of game1, got %+v", archive)
+	}
+
+	_, err = mem.ArchivedGame("game2")
+	if err == nil {
+		t.Error("expected error when retrieving the archive of an unknown game")
+	}
+}
+
+// entries records log entries written concurrently.
+type entries struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (e *entries) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lines = append(e.lines, string(p))
+
+	return len(p), nil
+}
+
+func TestMemoryGameLogger(t *testing.T) {
+	var out entries
+	logger, err := logging.New(logging.Config{Format: logging.JSON, Level: "debug", Output: &out})
+	if err != nil {
+		t.Fatalf("unexpected error setting logging up: %v", err)
+	}
+
+	// concrete memory test storage implementation
+	mem := memory.New(&logger)
+
+	game, err := mem.CreateGame(2, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	game.Close()
+
+	// game loggers derive from the root logger, not from the memory one
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	found := false
+	for _, line := range out.lines {
+		if strings.Count(line, `"`+logging.ComponentField+`":`) != 1 {
+			t.Errorf("expected a single component field, got %s", line)
+		}
+		if strings.Contains(line, `"`+logging.ComponentField+`":"game"`) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected log entries of the game, got %v", out.lines)
+	}
+}
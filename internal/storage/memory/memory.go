@@ -1,36 +1,40 @@
 package memory
 
 import (
-	"fmt"
-	"os"
-	"time"
+	"sync"
 
 	"github.com/rs/zerolog"
 
 	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 	"github.com/jtbonhomme/gameserver-websocket/internal/players"
 )
 
 type Memory struct {
-	log     *zerolog.Logger
-	players map[string]*players.Player
-	games   map[string]*games.Game
+	log      *zerolog.Logger
+	root     *zerolog.Logger // root is the logger the loggers of games derive from.
+	mu       sync.RWMutex    // mu guards the maps, accessed by RPCs and the matchmaker alike.
+	players  map[string]*players.Player
+	games    map[string]*games.Game
+	archives map[string]games.Archive
 }
 
 // New creates a new Memory object.
 func New(l *zerolog.Logger) *Memory {
-	output := zerolog.ConsoleWriter{
-		Out:           os.Stderr,
-		TimeFormat:    time.RFC3339,
-		FormatMessage: func(i interface{}) string { return fmt.Sprintf("[memory] %s", i) },
-	}
-	log := l.Output(output)
+	log := logging.Component(l, "memory")
 
 	mem := &Memory{
-		log:     &log,
-		players: make(map[string]*players.Player),
-		games:   make(map[string]*games.Game),
+		log:      &log,
+		root:     l,
+		players:  make(map[string]*players.Player),
+		games:    make(map[string]*games.Game),
+		archives: make(map[string]games.Archive),
 	}
 
 	return mem
 }
+
+// Ping returns nil, the memory being always reachable.
+func (m *Memory) Ping() error {
+	return nil
+}
@@ -1,12 +1,11 @@
 package memory_test
 
 import (
-	"fmt"
 	"os"
 	"sync"
 	"testing"
-	"time"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 	"github.com/jtbonhomme/gameserver-websocket/internal/manager"
 	"github.com/jtbonhomme/gameserver-websocket/internal/storage/memory"
 	"github.com/jtbonhomme/gameserver-websocket/internal/utils"
@@ -22,13 +21,10 @@ type Response struct {
 
 func newLogger() zerolog.Logger {
 	// Init logger
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	output := zerolog.ConsoleWriter{
-		Out:           os.Stderr,
-		TimeFormat:    time.RFC3339,
-		FormatMessage: func(i interface{}) string { return fmt.Sprintf("[client] %s", i) },
+	log, err := logging.New(logging.Config{Level: "debug"})
+	if err != nil {
+		panic(err)
 	}
-	log := zerolog.New(output).With().Timestamp().Logger()
 
 	return log
 }
@@ -42,7 +38,7 @@ func TestMain(m *testing.M) {
 
 	// concrete memory test storage implementation
 	s := memory.New(&log)
-	mgr = manager.New(&log, s)
+	mgr = manager.New(&log, s, manager.WithSeedAllowed())
 	err = mgr.Start()
 	if err != nil {
 		log.Err(err).Msg("error starting manager")
@@ -10,6 +10,9 @@ import (
 
 // ListPlayers returns all registered players (with ID anonymized).
 func (m *Memory) ListPlayers() []*players.Player {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	players := []*players.Player{}
 	for _, value := range m.players {
 		players = append(players, value)
@@ -19,6 +22,9 @@ func (m *Memory) ListPlayers() []*players.Player {
 
 // RegisterPlayer records a player with the given name.
 func (m *Memory) RegisterPlayer(id, name string) (*players.Player, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	// if provided id matches a registered player
 	if id != uuid.Nil.String() {
 		player, ok := m.players[id]
@@ -36,6 +42,9 @@ func (m *Memory) RegisterPlayer(id, name string) (*players.Player, error) {
 
 // UnregisterPlayer removes the player with a given ID.
 func (m *Memory) UnregisterPlayer(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	_, ok := m.players[id]
 	if !ok {
 		return fmt.Errorf("unknown player ID: %s", id)
@@ -46,6 +55,9 @@ func (m *Memory) UnregisterPlayer(id string) error {
 
 // PlayerByID returns a player object from its ID.
 func (m *Memory) PlayerByID(id string) (*players.Player, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	// if provided id matches a registered player
 	if id != uuid.Nil.String() {
 		player, ok := m.players[id]
@@ -56,3 +68,23 @@ func (m *Memory) PlayerByID(id string) (*players.Player, error) {
 
 	return nil, fmt.Errorf("unknown player id: %s", id)
 }
+
+// RecordRating records the rating of a player after a rated game, and
+// counts the game. The player is replaced rather than changed, so that
+// the players already returned are left untouched.
+func (m *Memory) RecordRating(id string, rating float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	player, ok := m.players[id]
+	if !ok {
+		return fmt.Errorf("unknown player id: %s", id)
+	}
+
+	rated := *player
+	rated.Rating = rating
+	rated.Games++
+	m.players[id] = &rated
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
 	"github.com/jtbonhomme/gameserver-websocket/internal/storage/memory"
 )
 
@@ -79,3 +80,37 @@ func TestMemoryPlayer(t *testing.T) {
 		t.Errorf("expected only registered player to be %s (%s) and got %s (%s)", player2.Name, player2.ID, players[0].Name, players[0].ID)
 	}
 }
+
+func TestMemoryRecordRating(t *testing.T) {
+	logger := zerolog.Nop()
+	mem := memory.New(&logger)
+
+	player, err := mem.RegisterPlayer("", "rated")
+	if err != nil {
+		t.Fatalf("error while registering player: %s", err.Error())
+	}
+	if player.Rating != players.InitialRating || player.Games != 0 {
+		t.Errorf("expected new player rated %f after 0 game, got %f after %d", players.InitialRating, player.Rating, player.Games)
+	}
+
+	err = mem.RecordRating("fake-id", 1600)
+	if err == nil {
+		t.Errorf("expected error while rating unknown player but got nil")
+	}
+
+	err = mem.RecordRating(player.ID.String(), 1516)
+	if err != nil {
+		t.Fatalf("error while rating player: %s", err.Error())
+	}
+
+	rated, err := mem.PlayerByID(player.ID.String())
+	if err != nil {
+		t.Fatalf("error while retrieving player: %s", err.Error())
+	}
+	if rated.Rating != 1516 || rated.Games != 1 {
+		t.Errorf("expected player rated 1516 after 1 game, got %f after %d", rated.Rating, rated.Games)
+	}
+	if player.Rating != players.InitialRating {
+		t.Errorf("expected player returned before rating to be unchanged, got %f", player.Rating)
+	}
+}
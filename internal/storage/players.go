@@ -10,4 +10,5 @@ type Players interface {
 	RegisterPlayer(string, string) (*players.Player, error) // Register records a player with the given name.
 	UnregisterPlayer(string) error                          // Unregister removes the player with a given ID.
 	PlayerByID(string) (*players.Player, error)             // PlayerByID returns a player object from its ID.
+	RecordRating(string, float64) error                     // RecordRating records the rating of a player after a rated game.
 }
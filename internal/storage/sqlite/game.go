@@ -2,9 +2,11 @@ package sqlite
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
 	"github.com/jtbonhomme/gameserver-websocket/internal/players"
 )
 
@@ -13,15 +15,22 @@ type Game struct {
 	ID         int
 	MinPlayers int
 	MaxPlayers int
+	Options    games.Options
 	Players    []*players.Player
 	Started    bool
 	StartTime  time.Time
 	EndTime    time.Time
 }
 
-// CreateGame creates a new game with the specified minimum and maximum number of players.
-func (s *SQLite) CreateGame(minPlayers, maxPlayers int) (*Game, error) {
-	result, err := s.db.Exec("INSERT INTO games (min_players, max_players) VALUES (?, ?)", minPlayers, maxPlayers)
+// CreateGame creates a new game with the specified minimum and maximum number of players,
+// and the game options stored as JSON.
+func (s *SQLite) CreateGame(minPlayers, maxPlayers int, options games.Options) (*Game, error) {
+	o, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal game options: %v", err)
+	}
+
+	result, err := s.db.Exec("INSERT INTO games (min_players, max_players, options) VALUES (?, ?, ?)", minPlayers, maxPlayers, string(o))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create game: %v", err)
 	}
@@ -35,6 +44,7 @@ func (s *SQLite) CreateGame(minPlayers, maxPlayers int) (*Game, error) {
 		ID:         int(gameID),
 		MinPlayers: minPlayers,
 		MaxPlayers: maxPlayers,
+		Options:    options,
 	}
 
 	return game, nil
@@ -103,9 +113,9 @@ func (s *SQLite) GetGameStats(gameID int) (*GameStats, error) {
 // HallOfFame represents the hall of fame of the best players.
 type HallOfFame []*players.Player
 
-// GetHallOfFame retrieves the hall of fame of the best players.
+// GetHallOfFame retrieves the hall of fame of the best rated players.
 func (s *SQLite) GetHallOfFame(limit int) (HallOfFame, error) {
-	rows, err := s.db.Query("SELECT players.id, players.name, SUM(scores.score) AS total_score FROM players JOIN scores ON players.id = scores.player_id GROUP BY players.id ORDER BY total_score DESC LIMIT ?", limit)
+	rows, err := s.db.Query("SELECT players.uid, players.name, players.rating, players.games FROM players WHERE players.games > 0 ORDER BY players.rating DESC LIMIT ?", limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get hall of fame: %v", err)
 	}
@@ -115,7 +125,7 @@ func (s *SQLite) GetHallOfFame(limit int) (HallOfFame, error) {
 
 	for rows.Next() {
 		player := &players.Player{}
-		err := rows.Scan(&player.ID, &player.Name, &player.Score)
+		err := rows.Scan(&player.ID, &player.Name, &player.Rating, &player.Games)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan hall of fame row: %v", err)
 		}
@@ -12,13 +12,15 @@ func (s *SQLite) MigrateSchema() error {
 		CREATE TABLE IF NOT EXISTS players (
 			uid UUID PRIMARY KEY,
 			name VARCHAR(255) UNIQUE,
-			score INT
+			rating REAL DEFAULT 1500,
+			games INT DEFAULT 0
 		);
 
 		CREATE TABLE IF NOT EXISTS games (
 			uid UUID PRIMARY KEY,
 			min_players INT,
 			max_players INT,
+			options TEXT,
 			started BOOL,
 			ended BOOL,
 			start_time TIMESTAMP,
@@ -20,8 +20,9 @@ func (s *SQLite) Register(id uuid.UUID, name string) (*players.Player, error) {
 	}
 
 	player := &players.Player{
-		ID:   playerID,
-		Name: name,
+		ID:     playerID,
+		Name:   name,
+		Rating: players.InitialRating,
 	}
 
 	_, err = s.db.Exec("INSERT INTO players (name, 0) VALUES (?)", name)
@@ -41,3 +42,13 @@ func (s *SQLite) Unregister(playerID uuid.UUID) error {
 
 	return nil
 }
+
+// RecordRating records the rating of a player after a rated game, and counts the game.
+func (s *SQLite) RecordRating(playerID uuid.UUID, rating float64) error {
+	_, err := s.db.Exec("UPDATE players SET rating = ?, games = games + 1 WHERE uid = ?", rating, playerID.String())
+	if err != nil {
+		return fmt.Errorf("failed to record rating: %v", err)
+	}
+
+	return nil
+}
@@ -23,3 +23,8 @@ func New(db *sql.DB) (*SQLite, error) {
 
 	return s, nil
 }
+
+// Ping returns an error when the database is not reachable.
+func (s *SQLite) Ping() error {
+	return s.db.Ping()
+}
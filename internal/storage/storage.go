@@ -2,6 +2,7 @@ package storage
 
 // Storage defines the interface for game manager storage.
 type Storage interface {
-	Players // Players defines the interface for players storage.
-	Games   // Games defines the interface for games storage.
+	Players      // Players defines the interface for players storage.
+	Games        // Games defines the interface for games storage.
+	Ping() error // Ping returns an error when the storage is not reachable.
 }
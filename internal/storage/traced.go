@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/games"
+	"github.com/jtbonhomme/gameserver-websocket/internal/players"
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+)
+
+// traced is a storage recording a span for each of its operations, as a
+// child of the span of its context.
+type traced struct {
+	ctx context.Context
+	s   Storage
+}
+
+// Traced returns a storage recording a span for each operation of s, as a
+// child of the span of ctx.
+func Traced(ctx context.Context, s Storage) Storage {
+	return &traced{ctx: ctx, s: s}
+}
+
+// start starts the span of an operation.
+func (t *traced) start(operation string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := tracing.Start(t.ctx, "storage."+operation, attrs...)
+	return span
+}
+
+// end ends the span of an operation, with its error if any.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *traced) ListPlayers() []*players.Player {
+	span := t.start("ListPlayers")
+	defer span.End()
+	return t.s.ListPlayers()
+}
+
+func (t *traced) RegisterPlayer(id, name string) (*players.Player, error) {
+	span := t.start("RegisterPlayer", tracing.PlayerID.String(id))
+	player, err := t.s.RegisterPlayer(id, name)
+	end(span, err)
+	return player, err
+}
+
+func (t *traced) UnregisterPlayer(id string) error {
+	span := t.start("UnregisterPlayer", tracing.PlayerID.String(id))
+	err := t.s.UnregisterPlayer(id)
+	end(span, err)
+	return err
+}
+
+func (t *traced) PlayerByID(id string) (*players.Player, error) {
+	span := t.start("PlayerByID", tracing.PlayerID.String(id))
+	player, err := t.s.PlayerByID(id)
+	end(span, err)
+	return player, err
+}
+
+func (t *traced) RecordRating(id string, rating float64) error {
+	span := t.start("RecordRating", tracing.PlayerID.String(id))
+	err := t.s.RecordRating(id, rating)
+	end(span, err)
+	return err
+}
+
+func (t *traced) ListGames() []*games.Game {
+	span := t.start("ListGames")
+	defer span.End()
+	return t.s.ListGames()
+}
+
+func (t *traced) CreateGame(minPlayers, maxPlayers int, opts ...games.Option) (*games.Game, error) {
+	span := t.start("CreateGame")
+	game, err := t.s.CreateGame(minPlayers, maxPlayers, opts...)
+	if game != nil {
+		span.SetAttributes(tracing.GameID.String(game.ID.String()))
+	}
+	end(span, err)
+	return game, err
+}
+
+func (t *traced) StartGame(id string) error {
+	span := t.start("StartGame", tracing.GameID.String(id))
+	err := t.s.StartGame(id)
+	end(span, err)
+	return err
+}
+
+func (t *traced) StopGame(id string) error {
+	span := t.start("StopGame", tracing.GameID.String(id))
+	err := t.s.StopGame(id)
+	end(span, err)
+	return err
+}
+
+func (t *traced) IsGameStarted(id string) (bool, error) {
+	span := t.start("IsGameStarted", tracing.GameID.String(id))
+	started, err := t.s.IsGameStarted(id)
+	end(span, err)
+	return started, err
+}
+
+func (t *traced) JoinGame(id, pID string) error {
+	span := t.start("JoinGame", tracing.GameID.String(id), tracing.PlayerID.String(pID))
+	err := t.s.JoinGame(id, pID)
+	end(span, err)
+	return err
+}
+
+func (t *traced) GameByID(id string) (*games.Game, error) {
+	span := t.start("GameByID", tracing.GameID.String(id))
+	game, err := t.s.GameByID(id)
+	end(span, err)
+	return game, err
+}
+
+func (t *traced) GameByCode(code string) (*games.Game, error) {
+	span := t.start("GameByCode")
+	game, err := t.s.GameByCode(code)
+	if game != nil {
+		span.SetAttributes(tracing.GameID.String(game.ID.String()))
+	}
+	end(span, err)
+	return game, err
+}
+
+func (t *traced) RemoveGame(id string) error {
+	span := t.start("RemoveGame", tracing.GameID.String(id))
+	err := t.s.RemoveGame(id)
+	end(span, err)
+	return err
+}
+
+func (t *traced) ArchiveGame(archive games.Archive) error {
+	span := t.start("ArchiveGame", tracing.GameID.String(archive.ID))
+	err := t.s.ArchiveGame(archive)
+	end(span, err)
+	return err
+}
+
+func (t *traced) ArchivedGame(id string) (games.Archive, error) {
+	span := t.start("ArchivedGame", tracing.GameID.String(id))
+	archive, err := t.s.ArchivedGame(id)
+	end(span, err)
+	return archive, err
+}
+
+func (t *traced) Ping() error {
+	span := t.start("Ping")
+	err := t.s.Ping()
+	end(span, err)
+	return err
+}
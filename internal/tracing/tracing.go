@@ -0,0 +1,100 @@
+// Package tracing sets up the OpenTelemetry tracing of the game server, and
+// defines the attributes of its spans. The spans of a RPC, of the storage
+// operations and of the publications it triggers belong to the same trace.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Name is the name of the tracer of the game server, and of its service.
+const Name = "gameserver"
+
+// Exporters of the spans.
+const (
+	None   string = ""
+	Stdout string = "stdout"
+	OTLP   string = "otlp"
+)
+
+// Attributes of the spans.
+const (
+	GameID   attribute.Key = "game.id"
+	PlayerID attribute.Key = "player.id"
+	Channel  attribute.Key = "channel"
+	Event    attribute.Key = "event.type"
+	Method   attribute.Key = "rpc.method"
+	Status   attribute.Key = "rpc.status"
+	Path     attribute.Key = "url.path"
+)
+
+// Config is the tracing configuration.
+type Config struct {
+	Exporter string    // Exporter is none (default), stdout or otlp.
+	Endpoint string    // Endpoint is the URL of the OTLP collector, eg http://localhost:4318.
+	Output   io.Writer // Output of the stdout exporter, stdout if nil.
+}
+
+// New sets the global tracer provider up from a configuration, and returns
+// the function flushing the spans and shutting the provider down. Without
+// exporter, spans are not recorded.
+func New(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.Exporter {
+	case None:
+		return func(context.Context) error { return nil }, nil
+	case Stdout:
+		out := cfg.Output
+		if out == nil {
+			out = os.Stdout
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(out))
+	case OTLP:
+		var endpoint *url.URL
+		endpoint, err = url.Parse(cfg.Endpoint)
+		if err != nil || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid OTLP endpoint %q, expected an URL", cfg.Endpoint)
+		}
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint.Host)}
+		if endpoint.Scheme == "http" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if endpoint.Path != "" && endpoint.Path != "/" {
+			opts = append(opts, otlptracehttp.WithURLPath(endpoint.Path))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("invalid trace exporter %q, expected %s or %s", cfg.Exporter, Stdout, OTLP)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s trace exporter: %s", cfg.Exporter, err.Error())
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(Name))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start starts a span with the tracer of the game server.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(Name).Start(ctx, name, trace.WithAttributes(attrs...))
+}
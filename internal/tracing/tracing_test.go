@@ -0,0 +1,83 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/tracing"
+)
+
+func TestStdout(t *testing.T) {
+	var out bytes.Buffer
+	shutdown, err := tracing.New(context.Background(), tracing.Config{Exporter: tracing.Stdout, Output: &out})
+	if err != nil {
+		t.Fatalf("unexpected error setting tracing up: %v", err)
+	}
+
+	_, span := tracing.Start(context.Background(), "rpc.startGame", tracing.GameID.String("g1"))
+	span.End()
+
+	err = shutdown(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error shutting tracing down: %v", err)
+	}
+
+	for _, expected := range []string{`"Name":"rpc.startGame"`, `"Key":"game.id"`, `"Value":"gameserver"`} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %s in exported spans, got %s", expected, out.String())
+		}
+	}
+}
+
+func TestOTLP(t *testing.T) {
+	// collector stand-in
+	received := make(chan string, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r.URL.Path
+		if !bytes.Contains(body, []byte("storage.GameByID")) {
+			t.Errorf("expected exported span in request body")
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	defer collector.Close()
+
+	shutdown, err := tracing.New(context.Background(), tracing.Config{Exporter: tracing.OTLP, Endpoint: collector.URL})
+	if err != nil {
+		t.Fatalf("unexpected error setting tracing up: %v", err)
+	}
+
+	_, span := tracing.Start(context.Background(), "storage.GameByID", tracing.GameID.String("g1"))
+	span.End()
+
+	err = shutdown(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error shutting tracing down: %v", err)
+	}
+
+	select {
+	case path := <-received:
+		if path != "/v1/traces" {
+			t.Errorf("expected spans exported to /v1/traces, got %s", path)
+		}
+	default:
+		t.Errorf("expected spans exported to the collector")
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, cfg := range []tracing.Config{
+		{Exporter: "jaeger"},
+		{Exporter: tracing.OTLP, Endpoint: "localhost"},
+	} {
+		_, err := tracing.New(context.Background(), cfg)
+		if err == nil {
+			t.Errorf("expected an error setting tracing up with %+v", cfg)
+		}
+	}
+}
@@ -6,11 +6,14 @@ import (
 
 	centrifuge "github.com/centrifugal/centrifuge-go"
 	"github.com/rs/zerolog"
+
+	"github.com/jtbonhomme/gameserver-websocket/internal/logging"
 )
 
 const (
 	DefaultWebsocketURL  string = "ws://localhost:8000/connection/websocket"
 	ServerPublishChannel string = "server-general"
+	AdminLogsChannel     string = "admin-logs"
 )
 
 type ClientOptions struct {
@@ -115,11 +118,11 @@ func Subscribe(log *zerolog.Logger, c *centrifuge.Client, topicName string, opts
 	}
 
 	subscription.OnError(func(e centrifuge.SubscriptionErrorEvent) {
-		log.Debug().Msgf("[%s] subscription error event: %s", topicName, e.Error.Error())
+		log.Debug().Str(logging.Channel, topicName).Msgf("subscription error event: %s", e.Error.Error())
 	})
 
 	var publicationHandler = func(e centrifuge.PublicationEvent) {
-		log.Debug().Msgf("[%s] publication event: %s", topicName, string(e.Data))
+		log.Debug().Str(logging.Channel, topicName).Msgf("publication event: %s", string(e.Data))
 	}
 
 	if subscriptionOpts.PublicationHandler != nil {
@@ -129,7 +132,7 @@ func Subscribe(log *zerolog.Logger, c *centrifuge.Client, topicName string, opts
 	subscription.OnPublication(publicationHandler)
 
 	subscription.OnSubscribed(func(e centrifuge.SubscribedEvent) {
-		log.Debug().Msgf("[%s] subscribed event", topicName)
+		log.Debug().Str(logging.Channel, topicName).Msg("subscribed event")
 		wg.Done()
 	})
 
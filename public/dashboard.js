@@ -0,0 +1,135 @@
+/**
+ * @fileoverview Live operations dashboard, fed by the server-sent events of
+ * the admin API: a snapshot of the server state every second, and each
+ * event as soon as it is published.
+ */
+
+const maxEvents = 50;
+
+let source = undefined;
+let snapshot = undefined;
+let events = [];
+
+/**
+ * Escape a string to be inserted in HTML
+ * @param {string} s - the string to escape
+ * @returns {string}
+ */
+function escape(s) {
+  const div = document.createElement('div');
+  div.innerText = s === undefined ? '' : String(s);
+  return div.innerHTML;
+}
+
+/**
+ * Connect to the dashboard stream with the admin token
+ */
+function connect() {
+  if (source) {
+    source.close();
+  }
+
+  const token = document.getElementById('token').value;
+  source = new EventSource('/admin/api/dashboard?token=' + encodeURIComponent(token));
+
+  source.onopen = () => setStatus(true);
+  source.onerror = () => setStatus(false);
+  source.addEventListener('snapshot', (e) => {
+    snapshot = JSON.parse(e.data);
+    events = snapshot.events.slice().reverse();
+    render();
+  });
+  source.addEventListener('event', (e) => {
+    events.unshift(JSON.parse(e.data));
+    events = events.slice(0, maxEvents);
+    renderEvents();
+  });
+}
+
+/**
+ * Show whether the dashboard is connected
+ * @param {boolean} connected - is the stream open?
+ */
+function setStatus(connected) {
+  const status = document.getElementById('status');
+  status.className = 'material-icons ' + (connected ? 'connected' : 'disconnected');
+  status.innerText = connected ? 'cloud_done' : 'cloud_off';
+}
+
+/**
+ * Render the server state
+ */
+function render() {
+  const states = { lobby: 0, running: 0, over: 0 };
+  snapshot.games.forEach((g) => states[g.state]++);
+
+  document.getElementById('clients').innerText = snapshot.clients;
+  document.getElementById('players').innerText = snapshot.players;
+  document.getElementById('queued').innerText = snapshot.queued;
+  document.getElementById('games').innerText = states.lobby + ' / ' + states.running + ' / ' + states.over;
+
+  document.getElementById('gamesDiv').innerHTML = snapshot.games.map(renderGame).join('');
+  renderEvents();
+  renderTimers();
+}
+
+/**
+ * Render a game card
+ * @param {Object} game - the game to render
+ * @returns {string}
+ */
+function renderGame(game) {
+  const seats = game.seats.map((seat) => {
+    const classes = ['seat'];
+    if (seat.id === game.current) classes.push('current');
+    if (seat.forfeited) classes.push('forfeited');
+
+    let icon = seat.connected ? 'person' : 'person_outline';
+    if (seat.bot) icon = 'android';
+
+    const score = game.scores[seat.id] === undefined ? '' : ' (' + game.scores[seat.id] + ')';
+    const ready = game.state === 'lobby' && seat.ready ? ' <i class="material-icons">check</i>' : '';
+    return '<span class="' + classes.join(' ') + '" title="' + escape(seat.id) + '">' +
+      '<i class="material-icons">' + icon + '</i> ' + escape(seat.name || seat.id) + escape(score) + ready + '</span>';
+  }).join('');
+
+  const timer = game.turnDeadline ?
+    '<span class="timer" data-deadline="' + escape(game.turnDeadline) + '"></span>' : '';
+
+  return '<div class="game-card ' + escape(game.state) + '" title="' + escape(game.id) + '">' +
+    '<b>' + escape(game.name) + '</b> ' + escape(game.type) + (game.private ? ' <i class="material-icons">lock</i>' : '') +
+    '<br>' + escape(game.state) + ' ' + game.seats.length + '/' + game.maxPlayers + ' ' + timer +
+    '<br>' + seats + '</div>';
+}
+
+/**
+ * Render the recent events, latest first
+ */
+function renderEvents() {
+  document.getElementById('events').innerHTML = events.map((e) => {
+    const time = new Date(e.time).toLocaleTimeString();
+    const player = e.player ? ' ' + e.player.substring(0, 8) : '';
+    const id = e.id ? ' ' + e.id.substring(0, 8) : '';
+    return '<li>' + escape(time) + ' <b>' + escape(e.type) + '</b>' + escape(id + player) + ' ' + escape(e.data) + '</li>';
+  }).join('');
+}
+
+/**
+ * Render the seconds left to play the current turns
+ */
+function renderTimers() {
+  document.querySelectorAll('.timer').forEach((timer) => {
+    const left = Math.max(0, (new Date(timer.dataset.deadline) - new Date()) / 1000);
+    timer.innerHTML = '<i class="material-icons">timer</i>' + left.toFixed(1) + 's';
+  });
+}
+
+setInterval(renderTimers, 100);
+
+window.addEventListener('load', () => {
+  const token = new URLSearchParams(window.location.hash.substring(1)).get('token');
+  if (token) {
+    document.getElementById('token').value = token;
+    connect();
+  }
+});